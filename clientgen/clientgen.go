@@ -0,0 +1,462 @@
+// Package clientgen generates typed Go client from the Registry.
+// The client reuses the original Go types passed to request.Request Body and response.Response Body,
+// instead of generating look-alike structs from the document.
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"go/format"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Opt func(*Generator) error
+
+type Generator struct {
+	packageName string
+	clientName  string
+}
+
+// WithPackageName set the package name of the generated file, default is client.
+func WithPackageName(name string) Opt {
+	return func(gen *Generator) error {
+		name = strings.TrimSpace(name)
+		if !token.IsIdentifier(name) {
+			return fmt.Errorf("package name '%s' is not valid identifier", name)
+		}
+
+		gen.packageName = name
+		return nil
+	}
+}
+
+// WithClientName set the struct name of the generated client, default is Client.
+func WithClientName(name string) Opt {
+	return func(gen *Generator) error {
+		name = strings.TrimSpace(name)
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("client name '%s' must be exported identifier", name)
+		}
+
+		gen.clientName = name
+		return nil
+	}
+}
+
+func NewGenerator(options ...Opt) (*Generator, error) {
+	gen := &Generator{
+		packageName: "client",
+		clientName:  "Client",
+	}
+
+	for _, option := range options {
+		if option != nil {
+			err := option(gen)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return gen, nil
+}
+
+// Generate returns gofmt-ed Go source of the typed client, one method for each route in the Registry.
+// The Go types must be importable, so types declared in main package, inside a function or generic types are not supported.
+func (g *Generator) Generate(reg *openapidoc.Registry) ([]byte, error) {
+	file := &fileData{
+		PackageName: g.packageName,
+		ClientName:  g.clientName,
+		imports:     newImports(),
+	}
+
+	usedNames := make(map[string]int)
+	for _, route := range reg.Routes() {
+		op, err := file.newOperation(route, usedNames)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+
+		file.Operations = append(file.Operations, op)
+	}
+
+	file.Imports = file.imports.list()
+
+	buf := &bytes.Buffer{}
+	err := clientTmpl.Execute(buf, file)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated source is not valid Go: %w", err)
+	}
+
+	return src, nil
+}
+
+type fileData struct {
+	PackageName string
+	ClientName  string
+	Imports     []importSpec
+	Operations  []operation
+	UseJSON     bool
+	UseBytes    bool
+
+	imports *imports
+}
+
+type operation struct {
+	Name         string
+	ResponseName string
+	Method       string
+	Path         string
+	PathExpr     string
+	Params       []param
+	Body         *requestBody
+	Responses    []responseCase
+}
+
+type param struct {
+	GoName   string
+	Name     string
+	In       string
+	Type     string
+	Required bool
+}
+
+type requestBody struct {
+	ContentType string
+	Type        string
+	JSON        bool
+}
+
+type responseCase struct {
+	Status    string
+	Field     string
+	Cond      string
+	ElemType  string
+	isDefault bool
+	isRange   bool
+}
+
+func (f *fileData) newOperation(route openapidoc.Route, usedNames map[string]int) (op operation, err error) {
+	name := route.OperationID
+	if name == "" {
+		name = strings.ToLower(route.Method) + " " + docutil.PathParamRegex.ReplaceAllString(route.Path, "by $1")
+	}
+
+	op = operation{
+		Name:   uniqueName(exportedName(name), usedNames),
+		Method: route.Method,
+		Path:   route.Path,
+	}
+	op.ResponseName = op.Name + "Response"
+
+	reqInfo := request.Info{}
+	if route.Request != nil {
+		reqInfo = route.Request.Info()
+	}
+
+	// register the imports used by this operation first,
+	// so the argument name can be checked against the import alias.
+	for _, pathParam := range reqInfo.PathParams {
+		_, _ = f.typeExpr(reflect.TypeOf(pathParam.Value))
+	}
+
+	for _, queryParam := range reqInfo.QueryParams {
+		_, _ = f.typeExpr(reflect.TypeOf(queryParam.Value))
+	}
+
+	for _, body := range reqInfo.Bodies {
+		_, _ = f.typeExpr(reflect.TypeOf(body))
+	}
+
+	for _, resp := range route.Responses {
+		for _, body := range resp.Info().Bodies {
+			_, _ = f.typeExpr(reflect.TypeOf(body))
+		}
+	}
+
+	// argument names are unique per method, and must not conflict with the variable in the method body or the import alias
+	argNames := map[string]int{"reqBody": 1}
+	for name, count := range f.imports.used {
+		argNames[name] = count
+	}
+
+	// path params, the type is the type of the PathParam Value.
+	// Path template parameter which is not documented is added as string.
+	pathParams := make(map[string]param)
+	for _, pathParam := range reqInfo.PathParams {
+		var typeExpr string
+		typeExpr, err = f.typeExpr(reflect.TypeOf(pathParam.Value))
+		if err != nil {
+			err = fmt.Errorf("path param %s: %w", pathParam.Name, err)
+			return
+		}
+
+		pathParams[pathParam.Name] = param{Name: pathParam.Name, In: "path", Type: typeExpr, Required: true}
+	}
+
+	pathExpr := make([]string, 0)
+	lastIdx := 0
+	for _, match := range docutil.PathParamRegex.FindAllStringSubmatchIndex(route.Path, -1) {
+		paramName := route.Path[match[2]:match[3]]
+		p, exist := pathParams[paramName]
+		if !exist {
+			p = param{Name: paramName, In: "path", Type: "string", Required: true}
+		}
+
+		p.GoName = uniqueName(unexportedName(paramName), argNames)
+		op.Params = append(op.Params, p)
+
+		if literal := route.Path[lastIdx:match[0]]; literal != "" {
+			pathExpr = append(pathExpr, strconv.Quote(literal))
+		}
+
+		pathExpr = append(pathExpr, fmt.Sprintf("url.PathEscape(fmt.Sprint(%s))", p.GoName))
+		lastIdx = match[1]
+	}
+
+	if literal := route.Path[lastIdx:]; literal != "" || len(pathExpr) <= 0 {
+		pathExpr = append(pathExpr, strconv.Quote(literal))
+	}
+
+	op.PathExpr = strings.Join(pathExpr, " + ")
+
+	// query params, optional query is passed as pointer and only added when not nil
+	for _, queryParam := range reqInfo.QueryParams {
+		var typeExpr string
+		typeExpr, err = f.typeExpr(reflect.TypeOf(queryParam.Value))
+		if err != nil {
+			err = fmt.Errorf("query param %s: %w", queryParam.Name, err)
+			return
+		}
+
+		if !queryParam.Required {
+			typeExpr = "*" + typeExpr
+		}
+
+		op.Params = append(op.Params, param{
+			GoName:   uniqueName(unexportedName(queryParam.Name), argNames),
+			Name:     queryParam.Name,
+			In:       "query",
+			Type:     typeExpr,
+			Required: queryParam.Required,
+		})
+	}
+
+	// header params is always string, optional header is only added when not empty
+	for _, headerKey := range docutil.SortedKeys(reqInfo.Headers) {
+		op.Params = append(op.Params, param{
+			GoName:   uniqueName(unexportedName(headerKey), argNames),
+			Name:     headerKey,
+			In:       "header",
+			Type:     "string",
+			Required: reqInfo.Headers[headerKey].Required,
+		})
+	}
+
+	// request body, JSON content type is preferred and encoded using the original type
+	if contentType := docutil.PreferredContentType(reqInfo.Bodies); len(reqInfo.Bodies) > 0 {
+		body := &requestBody{ContentType: contentType, Type: "io.Reader"}
+		if docutil.IsJSON(contentType) && reqInfo.Bodies[contentType] != nil {
+			body.JSON = true
+			body.Type, err = f.typeExpr(reflect.TypeOf(reqInfo.Bodies[contentType]))
+			if err != nil {
+				err = fmt.Errorf("request body: %w", err)
+				return
+			}
+
+			f.UseJSON = true
+			f.UseBytes = true
+		}
+
+		op.Body = body
+	}
+
+	// responses, only JSON body is decoded to the original type
+	for status, resp := range route.Responses {
+		var respCase responseCase
+		respCase, err = f.newResponseCase(status, resp)
+		if err != nil {
+			err = fmt.Errorf("response %s: %w", status, err)
+			return
+		}
+
+		if respCase.ElemType == "" {
+			continue
+		}
+
+		op.Responses = append(op.Responses, respCase)
+	}
+
+	// exact status first, then range status (2xx), and finally default
+	sort.Slice(op.Responses, func(i, j int) bool {
+		a, b := op.Responses[i], op.Responses[j]
+		if a.isDefault != b.isDefault {
+			return b.isDefault
+		}
+
+		if a.isRange != b.isRange {
+			return b.isRange
+		}
+
+		return a.Status < b.Status
+	})
+
+	return
+}
+
+func (f *fileData) newResponseCase(status string, resp *response.Response) (respCase responseCase, err error) {
+	respCase.Status = status
+
+	switch {
+	case status == "default":
+		respCase.isDefault = true
+		respCase.Field = "Default"
+
+	case len(status) == 3 && strings.HasSuffix(status, "xx"):
+		respCase.isRange = true
+		respCase.Field = "Status" + strings.ToUpper(status)
+		respCase.Cond = fmt.Sprintf("resp.StatusCode >= %c00 && resp.StatusCode < %c00", status[0], status[0]+1)
+
+	default:
+		var code int
+		code, err = strconv.Atoi(status)
+		if err != nil || code < 100 || code > 599 {
+			err = fmt.Errorf("invalid http status code '%s'", status)
+			return
+		}
+
+		respCase.Field = "Status" + status
+		respCase.Cond = fmt.Sprintf("resp.StatusCode == %d", code)
+	}
+
+	info := resp.Info()
+	contentType := docutil.PreferredContentType(info.Bodies)
+	if !docutil.IsJSON(contentType) || info.Bodies[contentType] == nil {
+		return
+	}
+
+	t := reflect.TypeOf(info.Bodies[contentType])
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	respCase.ElemType, err = f.typeExpr(t)
+	if err != nil {
+		return
+	}
+
+	f.UseJSON = true
+	return
+}
+
+// typeExpr returns the Go source expression of the type and register the package import.
+func (f *fileData) typeExpr(t reflect.Type) (string, error) {
+	if t == nil {
+		return "string", nil
+	}
+
+	if t.Name() != "" {
+		if strings.ContainsAny(t.Name(), "[]") {
+			return "", fmt.Errorf("generic type %s is not supported", t)
+		}
+
+		// predeclared type, i.e: int, string, error
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+
+		if t.PkgPath() == "main" || strings.HasSuffix(t.PkgPath(), "_test") {
+			return "", fmt.Errorf("type %s is declared in package %s which cannot be imported", t, t.PkgPath())
+		}
+
+		if !token.IsExported(t.Name()) {
+			return "", fmt.Errorf("type %s is not exported", t)
+		}
+
+		return f.imports.add(t.PkgPath()) + "." + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := f.typeExpr(t.Elem())
+		return "*" + elem, err
+
+	case reflect.Slice:
+		elem, err := f.typeExpr(t.Elem())
+		return "[]" + elem, err
+
+	case reflect.Array:
+		elem, err := f.typeExpr(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), err
+
+	case reflect.Map:
+		key, err := f.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+
+		elem, err := f.typeExpr(t.Elem())
+		return fmt.Sprintf("map[%s]%s", key, elem), err
+
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}", nil
+		}
+	}
+
+	return "", fmt.Errorf("unnamed type %s is not supported, declare it as named type", t)
+}
+
+// words split the name by non-alphanumeric characters, i.e: "X-Request-ID" returns [X Request ID]
+func words(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+}
+
+func exportedName(name string) string {
+	s := &strings.Builder{}
+	for _, word := range words(name) {
+		s.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	out := s.String()
+	if out == "" || !token.IsIdentifier(out) {
+		out = "Op" + out
+	}
+
+	return out
+}
+
+func unexportedName(name string) string {
+	out := exportedName(name)
+	out = strings.ToLower(out[:1]) + out[1:]
+	if token.IsKeyword(out) {
+		out += "Param"
+	}
+
+	return out
+}
+
+// uniqueName append number suffix if the name is already used.
+func uniqueName(name string, used map[string]int) string {
+	used[name]++
+	if used[name] == 1 {
+		return name
+	}
+
+	return uniqueName(fmt.Sprintf("%s%d", name, used[name]), used)
+}
@@ -0,0 +1,71 @@
+package clientgen_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/clientgen"
+	"github.com/yusufsyaifudin/openapidoc/clientgen/internal/petstore"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"go/format"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	gen, err := clientgen.NewGenerator(clientgen.WithPackageName("petclient"), clientgen.WithClientName("PetClient"))
+	assert.NoError(t, err)
+
+	src, err := gen.Generate(petstore.Registry())
+	assert.NoError(t, err)
+
+	formatted, err := format.Source(src)
+	assert.NoError(t, err)
+	assert.Equal(t, string(formatted), string(src), "generated source must be gofmt-clean")
+
+	srcStr := string(src)
+	assert.Contains(t, srcStr, "func (c *PetClient) CreatePet(ctx context.Context, xRequestID string, body petstore.PetCreateReq) (*CreatePetResponse, error)")
+	assert.Contains(t, srcStr, "func (c *PetClient) ListPets(ctx context.Context, kind *petstore.Kind, limit int) (*ListPetsResponse, error)")
+	assert.Contains(t, srcStr, "func (c *PetClient) GetPetsById(ctx context.Context, id int) (*GetPetsByIdResponse, error)")
+	assert.Contains(t, srcStr, "func (c *PetClient) UploadPhoto(ctx context.Context, id string, body io.Reader) (*UploadPhotoResponse, error)")
+	assert.Contains(t, srcStr, "Status4XX  *petstore.Error")
+	assert.Contains(t, srcStr, "Status200  *[]petstore.Pet")
+	assert.Contains(t, srcStr, "\tdefault:\n\t\tout.Default = new(petstore.Error)")
+
+	// compile the generated client inside this module, so it can import the petstore package
+	dir, err := os.MkdirTemp(".", ".clientgen-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = os.WriteFile(filepath.Join(dir, "client.go"), src, 0o644)
+	assert.NoError(t, err)
+
+	out, err := exec.Command("go", "vet", "./"+filepath.Base(dir)).CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func TestGenerateNotSupportedType(t *testing.T) {
+	gen, err := clientgen.NewGenerator()
+	assert.NoError(t, err)
+
+	// type declared in the test package cannot be imported by the generated client
+	_, err = gen.Generate(registryWithLocalType())
+	assert.Error(t, err)
+}
+
+type local struct {
+	ID int `json:"id"`
+}
+
+func registryWithLocalType() *openapidoc.Registry {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodGet, "/local",
+		request.NewRequest(),
+		map[string]*response.Response{"200": response.NewResponse().Body("application/json", local{})},
+	)
+
+	return reg
+}
@@ -0,0 +1,76 @@
+package clientgen
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// stdImports is always imported by the generated client, so the name cannot be used as alias.
+var stdImports = []string{"bytes", "context", "encoding/json", "fmt", "io", "net/http", "net/url", "strings"}
+
+type importSpec struct {
+	Alias string
+	Path  string
+}
+
+// imports keeps the alias of each imported package path.
+// Alias is always written, because the last element of the path may not be the package name, i.e: k8s.io/api/core/v1
+type imports struct {
+	aliases map[string]string // k = package path, v = alias
+	used    map[string]int    // k = alias
+}
+
+func newImports() *imports {
+	used := make(map[string]int)
+	for _, stdImport := range stdImports {
+		used[path.Base(stdImport)] = 1
+	}
+
+	// the identifier used in the generated source
+	for _, name := range []string{"c", "ctx", "body", "path", "query", "req", "reqBody", "resp", "respBody", "out", "err", "b", "u"} {
+		used[name] = 1
+	}
+
+	return &imports{
+		aliases: make(map[string]string),
+		used:    used,
+	}
+}
+
+// add returns the alias of the package path.
+func (i *imports) add(pkgPath string) string {
+	if alias, exist := i.aliases[pkgPath]; exist {
+		return alias
+	}
+
+	alias := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, path.Base(pkgPath))
+
+	if alias == "" || alias[0] >= '0' && alias[0] <= '9' {
+		alias = fmt.Sprintf("pkg%s", alias)
+	}
+
+	alias = uniqueName(alias, i.used)
+	i.aliases[pkgPath] = alias
+	return alias
+}
+
+func (i *imports) list() []importSpec {
+	specs := make([]importSpec, 0, len(i.aliases))
+	for pkgPath, alias := range i.aliases {
+		specs = append(specs, importSpec{Alias: alias, Path: pkgPath})
+	}
+
+	sort.Slice(specs, func(a, b int) bool {
+		return specs[a].Path < specs[b].Path
+	})
+
+	return specs
+}
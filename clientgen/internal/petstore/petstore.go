@@ -0,0 +1,71 @@
+// Package petstore is the Registry fixture to test the generated client.
+package petstore
+
+import (
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/header"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+)
+
+type Kind string
+
+type Pet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+}
+
+type PetCreateReq struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+func Registry() *openapidoc.Registry {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/pets",
+		request.NewRequest().
+			Body("application/json", PetCreateReq{}).
+			Header(header.NewHeader().Add("X-Request-ID", header.Map{Value: "abc", Required: true})),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", &Pet{}),
+			"4xx": response.NewResponse().Body("application/json", Error{}),
+		},
+		openapidoc.WithOperationID("createPet"),
+	)
+
+	reg.Add(http.MethodGet, "/pets",
+		request.NewRequest().QueryParams(
+			request.QueryParam{Name: "kind", Value: Kind("cat")},
+			request.QueryParam{Name: "limit", Value: 10, Required: true},
+		),
+		map[string]*response.Response{
+			"200":     response.NewResponse().Body("application/json", []Pet{}),
+			"default": response.NewResponse().Body("application/json", Error{}),
+		},
+		openapidoc.WithOperationID("list-pets"),
+	)
+
+	reg.Add(http.MethodGet, "/pets/{id}",
+		request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Pet{}),
+			"404": response.NewResponse().Body("application/json", Error{}),
+		},
+	)
+
+	reg.Add(http.MethodPut, "/pets/{id}/photo",
+		request.NewRequest().Body("image/png", []byte{}),
+		map[string]*response.Response{
+			"204": response.NewResponse(),
+		},
+		openapidoc.WithOperationID("uploadPhoto"),
+	)
+
+	return reg
+}
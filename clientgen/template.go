@@ -0,0 +1,158 @@
+package clientgen
+
+import "text/template"
+
+var clientTmpl = template.Must(template.New("client").Parse(`// Code generated by openapidoc clientgen. DO NOT EDIT.
+
+package {{ .PackageName }}
+
+import (
+{{- if .UseBytes }}
+	"bytes"
+{{- end }}
+	"context"
+{{- if .UseJSON }}
+	"encoding/json"
+{{- end }}
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+{{ range .Imports }}
+	{{ .Alias }} "{{ .Path }}"
+{{- end }}
+)
+
+// {{ .ClientName }} is the typed client of the API.
+type {{ .ClientName }} struct {
+	// BaseURL is the server URL without trailing slash, i.e: https://example.com/api
+	BaseURL string
+
+	// HTTPClient is used to send the request, http.DefaultClient is used when nil.
+	HTTPClient *http.Client
+}
+
+func New{{ .ClientName }}(baseURL string, httpClient *http.Client) *{{ .ClientName }} {
+	return &{{ .ClientName }}{
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
+	}
+}
+
+func (c *{{ .ClientName }}) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request %s %s: %w", method, path, err)
+	}
+
+	return req, nil
+}
+
+func (c *{{ .ClientName }}) do(req *http.Request) (*http.Response, []byte, error) {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	return resp, respBody, nil
+}
+{{ range $op := .Operations }}
+// {{ $op.ResponseName }} is the response of {{ $op.Name }}.
+// Only the field of the returned status code is set, Body always contains the raw response body.
+type {{ $op.ResponseName }} struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+{{- range $op.Responses }}
+	{{ .Field }} *{{ .ElemType }}
+{{- end }}
+}
+
+// {{ $op.Name }} calls {{ $op.Method }} {{ $op.Path }}
+func (c *{{ $.ClientName }}) {{ $op.Name }}(ctx context.Context{{ range $op.Params }}, {{ .GoName }} {{ .Type }}{{ end }}{{ if $op.Body }}, body {{ $op.Body.Type }}{{ end }}) (*{{ $op.ResponseName }}, error) {
+	path := {{ $op.PathExpr }}
+
+	query := url.Values{}
+{{- range $op.Params }}{{ if eq .In "query" }}{{ if .Required }}
+	query.Set("{{ .Name }}", fmt.Sprint({{ .GoName }}))
+{{- else }}
+	if {{ .GoName }} != nil {
+		query.Set("{{ .Name }}", fmt.Sprint(*{{ .GoName }}))
+	}
+{{- end }}{{ end }}{{ end }}
+
+	var reqBody io.Reader
+{{- if $op.Body }}{{ if $op.Body.JSON }}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encode {{ $op.Name }} request body: %w", err)
+	}
+
+	reqBody = bytes.NewReader(b)
+{{- else }}
+	reqBody = body
+{{- end }}{{ end }}
+
+	req, err := c.newRequest(ctx, "{{ $op.Method }}", path, query, reqBody)
+	if err != nil {
+		return nil, err
+	}
+{{ if $op.Body }}
+	req.Header.Set("Content-Type", "{{ $op.Body.ContentType }}")
+{{- end }}
+{{- range $op.Params }}{{ if eq .In "header" }}{{ if .Required }}
+	req.Header.Set("{{ .Name }}", {{ .GoName }})
+{{- else }}
+	if {{ .GoName }} != "" {
+		req.Header.Set("{{ .Name }}", {{ .GoName }})
+	}
+{{- end }}{{ end }}{{ end }}
+
+	resp, respBody, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &{{ $op.ResponseName }}{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}
+{{ if $op.Responses }}
+	if len(respBody) <= 0 {
+		return out, nil
+	}
+
+	switch {
+{{- range $i, $resp := $op.Responses }}
+{{- if $i }}
+{{ end }}
+	{{ if $resp.Cond }}case {{ $resp.Cond }}{{ else }}default{{ end }}:
+		out.{{ $resp.Field }} = new({{ $resp.ElemType }})
+		if err := json.Unmarshal(respBody, out.{{ $resp.Field }}); err != nil {
+			return out, fmt.Errorf("decode {{ $op.Name }} response %d: %w", resp.StatusCode, err)
+		}
+{{- end }}
+	}
+{{ end }}
+	return out, nil
+}
+{{ end }}`))
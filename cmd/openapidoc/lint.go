@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"sort"
+)
+
+// lint returns the documentation issues of the document.
+// Document must be loaded using openapi3.Loader, so all the references value is resolved.
+func lint(doc *openapi3.T) []string {
+	issues := make([]string, 0)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		operations := doc.Paths[path].Operations()
+
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			operation := operations[method]
+
+			if operation.OperationID == "" {
+				issues = append(issues, fmt.Sprintf("%s %s: missing operationId", method, path))
+			}
+
+			pathParams := make(map[string]struct{})
+			for _, paramRef := range operation.Parameters {
+				if paramRef.Value != nil && paramRef.Value.In == openapi3.ParameterInPath {
+					pathParams[paramRef.Value.Name] = struct{}{}
+				}
+			}
+
+			for _, name := range docutil.PathParams(path) {
+				if _, exist := pathParams[name]; !exist {
+					issues = append(issues, fmt.Sprintf("%s %s: path parameter '%s' is not documented", method, path, name))
+				}
+			}
+
+			statuses := make([]string, 0, len(operation.Responses))
+			for status := range operation.Responses {
+				statuses = append(statuses, status)
+			}
+
+			sort.Strings(statuses)
+
+			for _, status := range statuses {
+				respRef := operation.Responses[status]
+				if respRef.Value == nil || respRef.Value.Description == nil || *respRef.Value.Description == "" {
+					issues = append(issues, fmt.Sprintf("%s %s: response %s has empty description", method, path, status))
+				}
+			}
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	desc := "pet"
+	doc := &openapi3.T{
+		Paths: openapi3.Paths{
+			"/pets/{id}": &openapi3.PathItem{
+				Get: &openapi3.Operation{
+					OperationID: "getPet",
+					Parameters: openapi3.Parameters{
+						{Value: openapi3.NewPathParameter("id")},
+					},
+					Responses: openapi3.Responses{
+						"200": {Value: &openapi3.Response{Description: &desc}},
+					},
+				},
+				Delete: &openapi3.Operation{
+					Responses: openapi3.Responses{
+						"204": {Value: &openapi3.Response{}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"DELETE /pets/{id}: missing operationId",
+		"DELETE /pets/{id}: path parameter 'id' is not documented",
+		"DELETE /pets/{id}: response 204 has empty description",
+	}, lint(doc))
+}
+
+func TestOutputFormat(t *testing.T) {
+	format, err := outputFormat("", "openapi.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "json", format)
+
+	format, err = outputFormat("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "yaml", format)
+
+	_, err = outputFormat("toml", "openapi.toml")
+	assert.Error(t, err)
+}
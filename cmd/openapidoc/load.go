@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// entrypointTmpl is the main program to run the user entrypoint package,
+// it prints the generated document as JSON to the stdout.
+var entrypointTmpl = template.Must(template.New("main").Parse(`// Code generated by openapidoc. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	entrypoint "{{ .ImportPath }}"
+)
+
+func main() {
+	doc, err := entrypoint.{{ .FuncName }}().Generate()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	b, err := doc.MarshalJSON()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	_, _ = os.Stdout.Write(b)
+}
+`))
+
+// goPackage is the subset of go list -json output.
+type goPackage struct {
+	Dir        string
+	ImportPath string
+	Name       string
+}
+
+// loadEntrypoint builds and runs the entrypoint package, returns the generated document and its JSON.
+// The main program is written inside the package directory, so it uses the same go.mod as the entrypoint package.
+func loadEntrypoint(ctx context.Context, pkg, funcName string) (*openapi3.T, []byte, error) {
+	listOut, err := goCommand(ctx, "", "list", "-json", pkg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot find package %s: %w", pkg, err)
+	}
+
+	var goPkg goPackage
+	err = json.Unmarshal(listOut, &goPkg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse go list output of %s: %w", pkg, err)
+	}
+
+	if goPkg.Name == "main" {
+		return nil, nil, fmt.Errorf("package %s is main package and cannot be imported, move the %s function to other package", goPkg.ImportPath, funcName)
+	}
+
+	// directory started with dot is ignored by the go tool pattern, i.e: ./...
+	tmpDir, err := os.MkdirTemp(goPkg.Dir, ".openapidoc-")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	mainFile := &bytes.Buffer{}
+	err = entrypointTmpl.Execute(mainFile, map[string]string{
+		"ImportPath": goPkg.ImportPath,
+		"FuncName":   funcName,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = os.WriteFile(filepath.Join(tmpDir, "main.go"), mainFile.Bytes(), 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	docJSON, err := goCommand(ctx, goPkg.Dir, "run", "./"+filepath.Base(tmpDir))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot run entrypoint %s.%s: %w", goPkg.ImportPath, funcName, err)
+	}
+
+	// load using openapi3.Loader, so all references is resolved as the other OpenAPI tools read the document
+	doc, err := openapi3.NewLoader().LoadFromData(docJSON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse the generated document: %w", err)
+	}
+
+	return doc, docJSON, nil
+}
+
+func goCommand(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// encode the document JSON to the format.
+// YAML is written using the same way as the examples: JSON is decoded to interface first, so the key is sorted.
+func encode(docJSON []byte, format string) ([]byte, error) {
+	var doc interface{}
+	err := json.Unmarshal(docJSON, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "json" {
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(b, '\n'), nil
+	}
+
+	return utils.YamlMarshalIndent(doc)
+}
@@ -0,0 +1,354 @@
+// Command openapidoc generates, checks, validates, lints and diffs the OpenAPI document of a Registry.
+//
+// The entrypoint is a non-main package which exports a function returning the Registry, by default named Registry:
+//
+//	func Registry() *openapidoc.Registry
+//
+// It can be used from go generate, for example:
+//
+//	//go:generate go run github.com/yusufsyaifudin/openapidoc/cmd/openapidoc generate -o openapi.yaml
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/diff"
+	"github.com/yusufsyaifudin/openapidoc/export"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const usage = `openapidoc generates the OpenAPI document from a Registry entrypoint package.
+
+Usage:
+
+	openapidoc <command> [flags]
+
+Commands:
+
+	generate  write the document to a file or stdout, use -check to verify the committed file is up to date
+	validate  validate the document against the OpenAPI specification
+	lint      report documentation issues, such as missing operationId or description
+	diff      compare the document against a file and report breaking changes
+	export    write the document as Postman collection or .http request file
+
+Run 'openapidoc <command> -h' to see the flags of each command.
+`
+
+// exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		_, _ = fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var err error
+	code := exitOK
+	switch args[0] {
+	case "generate":
+		code, err = runGenerate(args[1:], stdout, stderr)
+	case "validate":
+		code, err = runValidate(args[1:], stdout, stderr)
+	case "lint":
+		code, err = runLint(args[1:], stdout, stderr)
+	case "diff":
+		code, err = runDiff(args[1:], stdout, stderr)
+	case "export":
+		code, err = runExport(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		_, _ = fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown command '%s'\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "openapidoc %s: %s\n", args[0], err)
+	}
+
+	return code
+}
+
+// entrypointFlags is the flags shared by all commands to locate the Registry entrypoint.
+type entrypointFlags struct {
+	pkg      string
+	funcName string
+}
+
+func (e *entrypointFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&e.pkg, "pkg", ".", "package path or directory which exports the Registry function")
+	fs.StringVar(&e.funcName, "func", "Registry", "name of the exported function with signature func() *openapidoc.Registry")
+}
+
+func (e *entrypointFlags) load() (*openapi3.T, []byte, error) {
+	return loadEntrypoint(context.Background(), e.pkg, e.funcName)
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func runGenerate(args []string, stdout, stderr io.Writer) (int, error) {
+	var (
+		entry  entrypointFlags
+		output string
+		format string
+		check  bool
+	)
+
+	fs := newFlagSet("generate", stderr)
+	entry.register(fs)
+	fs.StringVar(&output, "o", "", "output file, print to stdout if empty")
+	fs.StringVar(&format, "format", "", "output format: json or yaml, by default inferred from the output file extension or yaml")
+	fs.BoolVar(&check, "check", false, "do not write, exit with non-zero code if the output file is out of date")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+
+	if check && output == "" {
+		return exitUsage, fmt.Errorf("-check requires -o")
+	}
+
+	format, err := outputFormat(format, output)
+	if err != nil {
+		return exitUsage, err
+	}
+
+	_, docJSON, err := entry.load()
+	if err != nil {
+		return exitFailure, err
+	}
+
+	out, err := encode(docJSON, format)
+	if err != nil {
+		return exitFailure, err
+	}
+
+	switch {
+	case output == "":
+		_, err = stdout.Write(out)
+		if err != nil {
+			return exitFailure, err
+		}
+
+	case check:
+		current, err := os.ReadFile(output)
+		if err != nil {
+			return exitFailure, fmt.Errorf("cannot read %s: %w", output, err)
+		}
+
+		if !bytes.Equal(current, out) {
+			return exitFailure, fmt.Errorf("%s is out of date, run openapidoc generate to update it", output)
+		}
+
+	default:
+		err = os.MkdirAll(filepath.Dir(output), 0o755)
+		if err != nil {
+			return exitFailure, err
+		}
+
+		err = os.WriteFile(output, out, 0o644)
+		if err != nil {
+			return exitFailure, err
+		}
+	}
+
+	return exitOK, nil
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) (int, error) {
+	var entry entrypointFlags
+
+	fs := newFlagSet("validate", stderr)
+	entry.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+
+	doc, _, err := entry.load()
+	if err != nil {
+		return exitFailure, err
+	}
+
+	err = doc.Validate(context.Background())
+	if err != nil {
+		return exitFailure, err
+	}
+
+	_, _ = fmt.Fprintln(stdout, "document is valid")
+	return exitOK, nil
+}
+
+func runLint(args []string, stdout, stderr io.Writer) (int, error) {
+	var entry entrypointFlags
+
+	fs := newFlagSet("lint", stderr)
+	entry.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+
+	doc, _, err := entry.load()
+	if err != nil {
+		return exitFailure, err
+	}
+
+	issues := lint(doc)
+	for _, issue := range issues {
+		_, _ = fmt.Fprintln(stdout, issue)
+	}
+
+	if len(issues) > 0 {
+		return exitFailure, fmt.Errorf("found %d issues", len(issues))
+	}
+
+	return exitOK, nil
+}
+
+func runDiff(args []string, stdout, stderr io.Writer) (int, error) {
+	var (
+		entry          entrypointFlags
+		against        string
+		format         string
+		failOnBreaking bool
+	)
+
+	fs := newFlagSet("diff", stderr)
+	entry.register(fs)
+	fs.StringVar(&against, "against", "", "base document file (json or yaml) to compare with, i.e: the last released document")
+	fs.StringVar(&format, "format", "markdown", "output format: markdown or json")
+	fs.BoolVar(&failOnBreaking, "fail-on-breaking", true, "exit with non-zero code if there is breaking change")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+
+	if against == "" {
+		return exitUsage, fmt.Errorf("-against is required")
+	}
+
+	base, err := openapi3.NewLoader().LoadFromFile(against)
+	if err != nil {
+		return exitFailure, fmt.Errorf("cannot load %s: %w", against, err)
+	}
+
+	revision, _, err := entry.load()
+	if err != nil {
+		return exitFailure, err
+	}
+
+	result := diff.Compare(base, revision)
+	switch format {
+	case "markdown":
+		_, _ = fmt.Fprint(stdout, result.Markdown())
+
+	case "json":
+		b, err := result.JSON()
+		if err != nil {
+			return exitFailure, err
+		}
+
+		_, _ = fmt.Fprintln(stdout, string(b))
+
+	default:
+		return exitUsage, fmt.Errorf("unknown format '%s'", format)
+	}
+
+	if failOnBreaking && result.HasBreaking() {
+		return exitFailure, fmt.Errorf("found %d breaking changes", len(result.Breaking()))
+	}
+
+	return exitOK, nil
+}
+
+func runExport(args []string, stdout, stderr io.Writer) (int, error) {
+	var (
+		entry  entrypointFlags
+		output string
+		format string
+	)
+
+	fs := newFlagSet("export", stderr)
+	entry.register(fs)
+	fs.StringVar(&output, "o", "", "output file, print to stdout if empty")
+	fs.StringVar(&format, "format", "postman", "output format: postman or http")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+
+	var exporter func(doc *openapi3.T) ([]byte, error)
+	switch format {
+	case "postman":
+		exporter = export.Postman
+	case "http":
+		exporter = export.HTTPFile
+	default:
+		return exitUsage, fmt.Errorf("unknown format '%s'", format)
+	}
+
+	doc, _, err := entry.load()
+	if err != nil {
+		return exitFailure, err
+	}
+
+	out, err := exporter(doc)
+	if err != nil {
+		return exitFailure, err
+	}
+
+	if output == "" {
+		_, err = stdout.Write(out)
+		if err != nil {
+			return exitFailure, err
+		}
+
+		return exitOK, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(output), 0o755)
+	if err != nil {
+		return exitFailure, err
+	}
+
+	err = os.WriteFile(output, out, 0o644)
+	if err != nil {
+		return exitFailure, err
+	}
+
+	return exitOK, nil
+}
+
+// outputFormat returns json or yaml, if format is empty it is inferred from the file extension.
+func outputFormat(format, output string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = "yaml"
+		if strings.EqualFold(filepath.Ext(output), ".json") {
+			format = "json"
+		}
+	}
+
+	switch format {
+	case "json", "yaml":
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format '%s'", format)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// pkg is the entrypoint package used by the clientgen tests
+var pkg = filepath.Join("..", "..", "clientgen", "internal", "petstore")
+
+func TestRunGenerate(t *testing.T) {
+	t.Run("check requires output", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"generate", "-check"}, stdout, stderr)
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr.String(), "-check requires -o")
+	})
+
+	if testing.Short() {
+		t.Skip("generate builds and runs the entrypoint package")
+	}
+
+	output := filepath.Join(t.TempDir(), "docs", "openapi.yaml")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run([]string{"generate", "-pkg", pkg, "-o", output}, stdout, stderr)
+	if !assert.Equal(t, exitOK, code, stderr.String()) {
+		return
+	}
+
+	generated, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(generated), "/pets")
+
+	t.Run("stdout", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"generate", "-pkg", pkg}, stdout, stderr)
+		assert.Equal(t, exitOK, code, stderr.String())
+		assert.Equal(t, string(generated), stdout.String())
+	})
+
+	t.Run("check up to date", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"generate", "-pkg", pkg, "-o", output, "-check"}, stdout, stderr)
+		assert.Equal(t, exitOK, code, stderr.String())
+	})
+
+	t.Run("check out of date", func(t *testing.T) {
+		stale := filepath.Join(t.TempDir(), "openapi.yaml")
+		assert.NoError(t, os.WriteFile(stale, []byte("openapi: 3.0.0\n"), 0o644))
+
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"generate", "-pkg", pkg, "-o", stale, "-check"}, stdout, stderr)
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, stderr.String(), "is out of date")
+
+		// the file is not written when checking
+		current, err := os.ReadFile(stale)
+		assert.NoError(t, err)
+		assert.Equal(t, "openapi: 3.0.0\n", string(current))
+	})
+}
+
+func TestRunValidate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("validate builds and runs the entrypoint package")
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"validate", "-pkg", "./testdata/entrypoint"}, stdout, stderr)
+		assert.Equal(t, exitOK, code, stderr.String())
+		assert.Contains(t, stdout.String(), "document is valid")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		// the petstore document has no info version
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"validate", "-pkg", pkg}, stdout, stderr)
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, stderr.String(), "invalid info")
+		assert.Empty(t, stdout.String())
+	})
+}
+
+func TestRunDiff(t *testing.T) {
+	t.Run("against is required", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"diff"}, stdout, stderr)
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr.String(), "-against is required")
+	})
+
+	if testing.Short() {
+		t.Skip("diff builds and runs the entrypoint package")
+	}
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "openapi.yaml")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run([]string{"generate", "-pkg", pkg, "-o", base}, stdout, stderr)
+	if !assert.Equal(t, exitOK, code, stderr.String()) {
+		return
+	}
+
+	t.Run("no change", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"diff", "-pkg", pkg, "-against", base, "-format", "json"}, stdout, stderr)
+		assert.Equal(t, exitOK, code, stderr.String())
+	})
+
+	t.Run("breaking change", func(t *testing.T) {
+		// the base document has the operation which is removed in the revision
+		removed := filepath.Join(dir, "removed.yaml")
+		err := os.WriteFile(removed, []byte(`openapi: 3.0.0
+info:
+  title: Petstore
+  version: v1.0.0
+paths:
+  /removed:
+    get:
+      responses:
+        "200":
+          description: OK
+`), 0o644)
+		assert.NoError(t, err)
+
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"diff", "-pkg", pkg, "-against", removed}, stdout, stderr)
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, stdout.String(), "/removed")
+		assert.Contains(t, stderr.String(), "breaking changes")
+
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+		code = run([]string{"diff", "-pkg", pkg, "-against", removed, "-fail-on-breaking=false"}, stdout, stderr)
+		assert.Equal(t, exitOK, code, stderr.String())
+	})
+}
+
+func TestRunExport(t *testing.T) {
+	t.Run("unknown format", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"export", "-format", "har"}, stdout, stderr)
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr.String(), "unknown format 'har'")
+		assert.Empty(t, stdout.String())
+	})
+
+	if testing.Short() {
+		t.Skip("export builds and runs the entrypoint package")
+	}
+
+	t.Run("postman to stdout", func(t *testing.T) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"export", "-pkg", pkg}, stdout, stderr)
+		if !assert.Equal(t, exitOK, code, stderr.String()) {
+			return
+		}
+
+		var collection map[string]interface{}
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &collection))
+		assert.Contains(t, collection, "info")
+		assert.Contains(t, collection, "item")
+	})
+
+	t.Run("http file", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "requests", "petstore.http")
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"export", "-pkg", pkg, "-format", "http", "-o", output}, stdout, stderr)
+		if !assert.Equal(t, exitOK, code, stderr.String()) {
+			return
+		}
+
+		assert.Empty(t, stdout.String())
+		out, err := os.ReadFile(output)
+		assert.NoError(t, err)
+		assert.Contains(t, string(out), "GET {{baseUrl}}/pets")
+	})
+}
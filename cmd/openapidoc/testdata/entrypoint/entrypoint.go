@@ -0,0 +1,28 @@
+// Package entrypoint is the valid Registry entrypoint used by the command tests.
+package entrypoint
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+)
+
+type Pet struct {
+	ID   int    `json:"id" openapi3:"ex:1"`
+	Name string `json:"name" openapi3:"ex:Kitty"`
+}
+
+func Registry() *openapidoc.Registry {
+	reg := openapidoc.NewRegistry(openapidoc.WithServerInfo(&openapi3.Info{Title: "Pet", Version: "v1.0.0"}))
+	reg.Add(http.MethodGet, "/pets/{id}",
+		request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Pet{}),
+		},
+		openapidoc.WithOperationID("getPet"),
+	)
+
+	return reg
+}
@@ -0,0 +1,436 @@
+// Package contract replays the documented examples of the Registry against the real http.Handler.
+//
+// For each operation, the request is built from the documented path params, query params, headers and body examples.
+// The response status must be documented, and the response body must be valid against the schema of that status.
+//
+// Usage in the test:
+//
+//	runner, err := contract.NewRunner(reg, handler, contract.WithFixture("getPet", contract.Fixture{
+//		PathParams: map[string]string{"id": "1"},
+//		Setup: func(t testing.TB) { seedPet(t, 1) },
+//	}))
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	runner.Run(t)
+package contract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Fixture overrides the documented values of one operation.
+type Fixture struct {
+	// PathParams map k = path param name, v = value replacing the documented path param value
+	PathParams map[string]string
+
+	// QueryParams map k = query param name, v = value replacing the documented query param value
+	QueryParams map[string]string
+
+	// Headers map k = header key, v = value replacing the documented header value
+	Headers map[string]string
+
+	// Body replaces the documented body examples, ContentType is required when the operation has more than one content type.
+	Body        interface{}
+	ContentType string
+
+	// Setup is called before each request of the operation, i.e: to seed the database.
+	Setup func(t testing.TB)
+
+	// Skip the operation, i.e: the handler is not ready yet.
+	Skip bool
+}
+
+// Opt is the option of the Runner.
+type Opt func(*Runner) error
+
+// WithFixture sets the fixture of the operation.
+// Operation is either the operationId or the method and path, i.e: GET /pets/{id}
+func WithFixture(operation string, fixture Fixture) Opt {
+	return func(r *Runner) error {
+		operation = strings.TrimSpace(operation)
+		if operation == "" {
+			return fmt.Errorf("contract: fixture operation must not be empty")
+		}
+
+		r.fixtures[operation] = fixture
+		return nil
+	}
+}
+
+// WithSetup sets the hook which is called before each request of every operation.
+// It is called before the Fixture.Setup.
+func WithSetup(setup func(t testing.TB, route openapidoc.Route)) Opt {
+	return func(r *Runner) error {
+		r.setup = setup
+		return nil
+	}
+}
+
+// Runner replays the documented examples against the http.Handler.
+type Runner struct {
+	handler http.Handler
+	doc     *openapi3.T
+	routes  []openapidoc.Route
+
+	// fixtures map k = operationId or "METHOD /path", v = the fixture
+	fixtures map[string]Fixture
+	setup    func(t testing.TB, route openapidoc.Route)
+}
+
+// Result is the result of one replayed request.
+type Result struct {
+	Method      string
+	Path        string
+	OperationID string
+
+	// Case is the name of the body example, empty if the request has no named example.
+	Case   string
+	Status int
+	Errors []error
+}
+
+// Passed returns true if the response is documented and valid.
+func (r Result) Passed() bool {
+	return len(r.Errors) <= 0
+}
+
+// NewRunner returns the Runner of all operations registered in the Registry.
+func NewRunner(reg *openapidoc.Registry, handler http.Handler, options ...Opt) (*Runner, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("contract: handler must not be nil")
+	}
+
+	r := &Runner{
+		handler:  handler,
+		fixtures: make(map[string]Fixture),
+	}
+
+	for _, option := range options {
+		if option != nil {
+			err := option(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	doc, err := reg.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	// load the document again, so all references value is resolved and can be used by openapi3filter
+	docJSON, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	r.doc, err = openapi3.NewLoader().LoadFromData(docJSON)
+	if err != nil {
+		return nil, fmt.Errorf("contract: cannot load the generated document: %w", err)
+	}
+
+	r.routes = reg.Routes()
+	return r, nil
+}
+
+// Run replays all operations as sub-tests named by the method and path, and reports the failed one using t.Errorf.
+// Operation with more than one body example has sub-test per example.
+func (r *Runner) Run(t *testing.T) []Result {
+	t.Helper()
+
+	results := make([]Result, 0)
+	for _, route := range r.routes {
+		route := route
+		fixture := r.fixture(route)
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			if fixture.Skip {
+				t.Skip("skipped by fixture")
+			}
+
+			cases, err := r.cases(route, fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, c := range cases {
+				c := c
+				run := func(t *testing.T) {
+					result := r.replay(t, route, fixture, c)
+					results = append(results, result)
+					for _, err := range result.Errors {
+						t.Errorf("%s %s: %s", route.Method, route.Path, err)
+					}
+				}
+
+				if len(cases) == 1 {
+					run(t)
+					continue
+				}
+
+				t.Run(c.name, run)
+			}
+		})
+	}
+
+	return results
+}
+
+// Verify replays all operations and returns the results without reporting the failure,
+// t is only passed to the setup hooks.
+func (r *Runner) Verify(t testing.TB) []Result {
+	t.Helper()
+
+	results := make([]Result, 0)
+	for _, route := range r.routes {
+		fixture := r.fixture(route)
+		if fixture.Skip {
+			continue
+		}
+
+		cases, err := r.cases(route, fixture)
+		if err != nil {
+			results = append(results, Result{
+				Method:      route.Method,
+				Path:        route.Path,
+				OperationID: route.OperationID,
+				Errors:      []error{err},
+			})
+			continue
+		}
+
+		for _, c := range cases {
+			results = append(results, r.replay(t, route, fixture, c))
+		}
+	}
+
+	return results
+}
+
+func (r *Runner) fixture(route openapidoc.Route) Fixture {
+	if fixture, exist := r.fixtures[route.Method+" "+route.Path]; exist {
+		return fixture
+	}
+
+	if route.OperationID != "" {
+		if fixture, exist := r.fixtures[route.OperationID]; exist {
+			return fixture
+		}
+	}
+
+	return Fixture{}
+}
+
+// replayCase is one request to replay.
+type replayCase struct {
+	name        string
+	contentType string
+	body        interface{}
+}
+
+// cases returns one case per body example of the chosen content type.
+func (r *Runner) cases(route openapidoc.Route, fixture Fixture) ([]replayCase, error) {
+	if route.Request == nil {
+		return []replayCase{{}}, nil
+	}
+
+	info := route.Request.Info()
+	if len(info.Bodies) <= 0 {
+		return []replayCase{{}}, nil
+	}
+
+	contentType := fixture.ContentType
+	if contentType == "" {
+		contentType = docutil.PreferredContentType(info.Bodies)
+	}
+
+	if _, exist := info.Bodies[contentType]; !exist {
+		return nil, fmt.Errorf("content type %s is not documented", contentType)
+	}
+
+	if fixture.Body != nil {
+		return []replayCase{{contentType: contentType, body: fixture.Body}}, nil
+	}
+
+	examples := info.Examples[contentType]
+	if len(examples) <= 0 {
+		return []replayCase{{contentType: contentType, body: info.Bodies[contentType]}}, nil
+	}
+
+	cases := make([]replayCase, 0, len(examples))
+	for _, example := range examples {
+		cases = append(cases, replayCase{name: example.Name, contentType: contentType, body: example.Value})
+	}
+
+	return cases, nil
+}
+
+func (r *Runner) replay(t testing.TB, route openapidoc.Route, fixture Fixture, c replayCase) Result {
+	t.Helper()
+
+	result := Result{
+		Method:      route.Method,
+		Path:        route.Path,
+		OperationID: route.OperationID,
+		Case:        c.name,
+	}
+
+	if r.setup != nil {
+		r.setup(t, route)
+	}
+
+	if fixture.Setup != nil {
+		fixture.Setup(t)
+	}
+
+	req, err := newRequest(route, fixture, c)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+
+	rec := httptest.NewRecorder()
+	r.handler.ServeHTTP(rec, req)
+	result.Status = rec.Code
+
+	err = r.validateResponse(req, route, rec)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
+	return result
+}
+
+// validateResponse checks that the status is documented and the body is valid against the schema of the status.
+func (r *Runner) validateResponse(req *http.Request, route openapidoc.Route, rec *httptest.ResponseRecorder) error {
+	pathItem := r.doc.Paths[route.Path]
+	if pathItem == nil {
+		return fmt.Errorf("path is not found in the generated document")
+	}
+
+	op := pathItem.Operations()[route.Method]
+	if op == nil {
+		return fmt.Errorf("operation is not found in the generated document")
+	}
+
+	status := strconv.Itoa(rec.Code)
+	var respRef *openapi3.ResponseRef
+	for _, s := range []string{status, status[:1] + "XX", status[:1] + "xx", "default"} {
+		if respRef = op.Responses[s]; respRef != nil {
+			break
+		}
+	}
+
+	if respRef == nil {
+		return fmt.Errorf("status %d is not documented, body: %s", rec.Code, rec.Body.String())
+	}
+
+	// openapi3filter only lookup the exact status, so the matched response is used as the exact status
+	matchedOp := *op
+	matchedOp.Responses = openapi3.Responses{status: respRef}
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request: req,
+			Route: &routers.Route{
+				Spec:      r.doc,
+				Path:      route.Path,
+				PathItem:  pathItem,
+				Method:    route.Method,
+				Operation: &matchedOp,
+			},
+		},
+		Status: rec.Code,
+		Header: rec.Header(),
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+		},
+	}
+
+	input.SetBodyBytes(rec.Body.Bytes())
+	err := openapi3filter.ValidateResponse(context.Background(), input)
+	if err != nil {
+		return fmt.Errorf("status %d: %w", rec.Code, err)
+	}
+
+	return nil
+}
+
+// newRequest builds the http request from the documented values, overridden by the fixture.
+func newRequest(route openapidoc.Route, fixture Fixture, c replayCase) (*http.Request, error) {
+	var info request.Info
+	if route.Request != nil {
+		info = route.Request.Info()
+	}
+
+	pathParams := make(map[string]string)
+	for _, param := range info.PathParams {
+		if param.Value != nil {
+			pathParams[param.Name] = fmt.Sprint(param.Value)
+		}
+	}
+
+	for name, value := range fixture.PathParams {
+		pathParams[name] = value
+	}
+
+	path := docutil.ExpandPath(route.Path, pathParams)
+	if strings.Contains(path, "{") {
+		return nil, fmt.Errorf("path params of %s are not documented nor set in the fixture", path)
+	}
+
+	query := url.Values{}
+	for _, param := range info.QueryParams {
+		if param.Value != nil {
+			query.Set(param.Name, fmt.Sprint(param.Value))
+		}
+	}
+
+	for name, value := range fixture.QueryParams {
+		query.Set(name, value)
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	body, err := docutil.Encode(c.contentType, c.body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode request body: %w", err)
+	}
+
+	req := httptest.NewRequest(route.Method, path, bytes.NewReader(body))
+	for key, value := range info.Headers {
+		if value.Value != "" {
+			req.Header.Set(key, value.Value)
+		}
+	}
+
+	for key, value := range fixture.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if c.contentType != "" {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+
+	return req, nil
+}
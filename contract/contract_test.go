@@ -0,0 +1,138 @@
+package contract_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/contract"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type Pet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func newRegistry() *openapidoc.Registry {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/pets",
+		request.NewRequest().Body("application/json", Pet{}, request.WithExamples(
+			request.Example{Name: "kitty", Value: Pet{ID: 1, Name: "Kitty"}},
+			request.Example{Name: "doggy", Value: Pet{ID: 2, Name: "Doggy"}},
+		)),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", Pet{}),
+		},
+		openapidoc.WithOperationID("createPet"),
+	)
+
+	reg.Add(http.MethodGet, "/pets/{id}",
+		request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Pet{}),
+			"4XX": response.NewResponse().Body("application/json", map[string]string{"message": "not found"}),
+		},
+		openapidoc.WithOperationID("getPet"),
+	)
+
+	return reg
+}
+
+// petHandler is the handler under test, the pets map is the storage seeded by the setup hook.
+func petHandler(pets map[string]Pet, getBody func(pet Pet) interface{}) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pets", func(w http.ResponseWriter, r *http.Request) {
+		var pet Pet
+		_ = json.NewDecoder(r.Body).Decode(&pet)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(pet)
+	})
+
+	mux.HandleFunc("/pets/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		pet, exist := pets[strings.TrimPrefix(r.URL.Path, "/pets/")]
+		if !exist {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "not found"})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(getBody(pet))
+	})
+
+	return mux
+}
+
+func TestRunner(t *testing.T) {
+	pets := map[string]Pet{}
+	handler := petHandler(pets, func(pet Pet) interface{} { return pet })
+
+	setupCalled := 0
+	runner, err := contract.NewRunner(newRegistry(), handler,
+		contract.WithSetup(func(t testing.TB, route openapidoc.Route) {
+			setupCalled++
+		}),
+		contract.WithFixture("getPet", contract.Fixture{
+			PathParams: map[string]string{"id": "7"},
+			Setup: func(t testing.TB) {
+				pets["7"] = Pet{ID: 7, Name: "Bird"}
+			},
+		}),
+	)
+	assert.NoError(t, err)
+
+	results := runner.Run(t)
+	assert.Len(t, results, 3)
+	assert.Equal(t, 3, setupCalled)
+	for _, result := range results {
+		assert.True(t, result.Passed(), result.Errors)
+	}
+}
+
+func TestRunnerVerify(t *testing.T) {
+	t.Run("invalid response body", func(t *testing.T) {
+		handler := petHandler(map[string]Pet{"1": {ID: 1}}, func(pet Pet) interface{} {
+			return map[string]interface{}{"id": "one"}
+		})
+
+		runner, err := contract.NewRunner(newRegistry(), handler)
+		assert.NoError(t, err)
+
+		results := runner.Verify(t)
+		assert.Len(t, results, 3)
+		for _, result := range results {
+			assert.Equal(t, result.OperationID != "getPet", result.Passed(), result.OperationID)
+		}
+	})
+
+	t.Run("undocumented status", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		runner, err := contract.NewRunner(newRegistry(), handler, contract.WithFixture("POST /pets", contract.Fixture{Skip: true}))
+		assert.NoError(t, err)
+
+		results := runner.Verify(t)
+		assert.Len(t, results, 1)
+		assert.False(t, results[0].Passed())
+		assert.Equal(t, http.StatusInternalServerError, results[0].Status)
+		assert.Contains(t, results[0].Errors[0].Error(), "not documented")
+	})
+
+	t.Run("range status", func(t *testing.T) {
+		runner, err := contract.NewRunner(newRegistry(), petHandler(map[string]Pet{}, nil))
+		assert.NoError(t, err)
+
+		for _, result := range runner.Verify(t) {
+			assert.True(t, result.Passed(), result.Errors)
+		}
+	})
+}
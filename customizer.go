@@ -1,88 +1,131 @@
 package openapidoc
 
 import (
+	"errors"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/yusufsyaifudin/openapidoc/utils"
 	"reflect"
-	"strconv"
-	"strings"
 )
 
-var customizer = func(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
-	tagValue := tag.Get("openapi3")
-	tagSplit := strings.Split(tagValue, ",")
-
-	tagMap := make(map[string]string)
-	for _, val := range tagSplit {
-		kv := strings.Split(val, ":")
-		kvLen := len(kv)
-		switch {
-		case kvLen >= 2:
-			tagMap[kv[0]] = strings.ReplaceAll(strings.Join(kv[1:], " "), "'", "")
-		case kvLen == 1:
-			tagMap[kv[0]] = ""
+// schemaCustomizer returns the customizer of the default generator using the config.
+// It is applied to the generated schema in this order:
+// * provider: the schema of utils.SchemaProvider or WithTypeSchema replaces the generated one
+// * enum: utils.OpenAPIEnum or the constants if enabled, see utils.ApplyEnum
+// * openapi3 tag, see utils.ApplyOpenAPITag
+// * validate tag, if enabled
+// * required fields using the required policy, see utils.ApplyRequiredFields
+//
+// The fields of the provided type is not checked, so the provided schema takes precedence like json.Marshaler.
+func (c *Config) schemaCustomizer() openapi3gen.SchemaCustomizerFn {
+	customize := func(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+		// the schema of utils.SchemaProvider or WithTypeSchema replaces the generated one, then the tags is applied to it
+		if provided, ok := c.typeSchemas.Schema(t); ok {
+			*schema = *provided
+		}
+
+		applyEnum := utils.ApplyEnum
+		if c.constEnums != nil {
+			applyEnum = c.constEnums.ApplyEnum
+		}
+
+		if err := applyEnum(t, schema); err != nil {
+			return &utils.FieldError{Name: name, Type: t, Err: err}
+		}
+
+		if err := utils.ApplyOpenAPITag(name, t, tag.Get(utils.OpenAPITag), schema, c.strictTags); err != nil {
+			return err
 		}
-	}
 
-	for k, v := range tagMap {
-		var (
-			desc          string
-			exampleVal    interface{}
-			requiredField []string // only valid for type object
-		)
-
-		switch k {
-		case "desc":
-			desc = v
-
-		case "ex":
-			switch t.Kind() {
-			case reflect.String:
-				vArr := strings.Split(v, ";")
-				if len(vArr) > 0 {
-					exampleVal = vArr[len(vArr)-1]
-				} else {
-					exampleVal = v
-				}
-
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-				vInt, _ := strconv.Atoi(v)
-				exampleVal = vInt
-
-			case reflect.Float32, reflect.Float64:
-				vFloat, _ := strconv.ParseFloat(v, 64)
-				exampleVal = vFloat
-
-			case reflect.Bool:
-				vBool, _ := strconv.ParseBool(v)
-				exampleVal = vBool
-
-			case reflect.Array, reflect.Slice:
-				exampleVal = strings.Split(v, ";")
-
-			default:
-				exampleVal = v
+		if c.validatorTags {
+			if err := utils.ApplyValidatorTags(t, schema); err != nil {
+				return err
 			}
+		}
+
+		return utils.ApplyRequiredFields(name, t, schema, c.requiredPolicy)
+	}
+
+	// pending is the errors of the customized schemas, which is only returned when the root schema is customized.
+	// openapi3gen calls the customizer after the fields, so the fields of the type which schema is replaced by the provider
+	// is customized too. Their errors is ignored, because their schema is not in the root schema anymore.
+	var pending []schemaError
+	return func(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+		err := customize(name, t, tag, schema)
+
+		// the struct tag tells apart the fields with the same name and type, see utils.NewSchemaError
+		var fieldErr *utils.FieldError
+		if errors.As(err, &fieldErr) && fieldErr.Tag == "" && fieldErr.Name == name {
+			fieldErr.Tag = tag
+		}
+
+		if err != nil {
+			pending = append(pending, schemaError{schema: schema, err: err})
+		}
+
+		if name != rootSchemaName {
+			return nil
+		}
 
-		case "required":
-			switch t.Kind() {
-			case reflect.Map, reflect.Struct, reflect.Pointer:
-				requiredField = strings.Split(v, ";")
+		// the first error in the generated order is returned, as if it is returned immediately
+		errs := pending
+		pending = nil
+		reachable := schemaSet(schema)
+		for _, schemaErr := range errs {
+			if _, exist := reachable[schemaErr.schema]; exist {
+				return schemaErr.err
 			}
 		}
 
-		if desc != "" {
-			schema.Description = desc
+		return nil
+	}
+}
+
+// rootSchemaName is the name passed by openapi3gen to the customizer of the root type and its elements.
+const rootSchemaName = "_root"
+
+// schemaError is the error of customizing the schema.
+type schemaError struct {
+	schema *openapi3.Schema
+	err    error
+}
+
+// schemaSet returns the schema and all schemas inside it.
+func schemaSet(schema *openapi3.Schema) map[*openapi3.Schema]struct{} {
+	set := make(map[*openapi3.Schema]struct{})
+
+	var walk func(schema *openapi3.Schema)
+	walkRef := func(ref *openapi3.SchemaRef) {
+		if ref != nil {
+			walk(ref.Value)
+		}
+	}
+
+	walk = func(schema *openapi3.Schema) {
+		if schema == nil {
+			return
 		}
 
-		if exampleVal != nil {
-			schema.Example = exampleVal
+		if _, exist := set[schema]; exist {
+			return
 		}
 
-		if len(requiredField) > 0 {
-			schema.Required = requiredField
+		set[schema] = struct{}{}
+		for _, property := range schema.Properties {
+			walkRef(property)
 		}
+
+		for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+			for _, ref := range refs {
+				walkRef(ref)
+			}
+		}
+
+		walkRef(schema.Items)
+		walkRef(schema.AdditionalProperties)
+		walkRef(schema.Not)
 	}
 
-	return nil
+	walk(schema)
+	return set
 }
@@ -0,0 +1,591 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"sort"
+	"strings"
+)
+
+// Level is the impact of the change to the existing client.
+type Level string
+
+const (
+	// Breaking is the change that may break the existing client.
+	Breaking Level = "breaking"
+
+	// NonBreaking is the change that is safe for the existing client.
+	NonBreaking Level = "non-breaking"
+)
+
+// Change is one difference between base and revision document.
+type Change struct {
+	Level    Level  `json:"level"`
+	Kind     string `json:"kind"`
+	Method   string `json:"method,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Result contains all changes sorted by path, method and location.
+type Result struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreaking returns true if at least one change is breaking.
+func (r Result) HasBreaking() bool {
+	return len(r.Breaking()) > 0
+}
+
+// Breaking returns only the breaking changes.
+func (r Result) Breaking() []Change {
+	return r.filter(Breaking)
+}
+
+// NonBreaking returns only the non-breaking changes.
+func (r Result) NonBreaking() []Change {
+	return r.filter(NonBreaking)
+}
+
+func (r Result) filter(level Level) []Change {
+	changes := make([]Change, 0)
+	for _, change := range r.Changes {
+		if change.Level == level {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// JSON returns the machine-readable output of the Result.
+func (r Result) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown returns the changelog of the Result, breaking changes is written first.
+func (r Result) Markdown() string {
+	buf := &strings.Builder{}
+	buf.WriteString("# API Changes\n")
+
+	if len(r.Changes) <= 0 {
+		buf.WriteString("\nNo changes.\n")
+		return buf.String()
+	}
+
+	sections := []struct {
+		title   string
+		changes []Change
+	}{
+		{title: "Breaking changes", changes: r.Breaking()},
+		{title: "Non-breaking changes", changes: r.NonBreaking()},
+	}
+
+	for _, section := range sections {
+		if len(section.changes) <= 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(buf, "\n## %s\n\n", section.title)
+		for _, change := range section.changes {
+			buf.WriteString("- ")
+			if change.Method != "" {
+				_, _ = fmt.Fprintf(buf, "`%s %s` ", change.Method, change.Path)
+			} else if change.Path != "" {
+				_, _ = fmt.Fprintf(buf, "`%s` ", change.Path)
+			}
+
+			if change.Location != "" {
+				_, _ = fmt.Fprintf(buf, "%s: ", change.Location)
+			}
+
+			buf.WriteString(change.Message)
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.String()
+}
+
+// Compare returns all changes from base to revision document,
+// for example base is the last released document and revision is the output of Registry.Generate.
+func Compare(base, revision *openapi3.T) Result {
+	c := &comparer{
+		base:     base,
+		revision: revision,
+		changes:  make([]Change, 0),
+	}
+
+	c.comparePaths()
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		a, b := c.changes[i], c.changes[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+
+		return a.Kind < b.Kind
+	})
+
+	return Result{Changes: c.changes}
+}
+
+// direction of the payload, change in the request schema has different impact with change in the response schema.
+// I.e: new required property is breaking in request, but safe in response.
+type direction int
+
+const (
+	directionRequest direction = iota
+	directionResponse
+)
+
+// operation is the location of the current compared operation
+type operation struct {
+	method string
+	path   string
+}
+
+type comparer struct {
+	base     *openapi3.T
+	revision *openapi3.T
+	changes  []Change
+}
+
+func (c *comparer) add(level Level, kind string, op operation, location, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Level:    level,
+		Kind:     kind,
+		Method:   op.method,
+		Path:     op.path,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) comparePaths() {
+	for _, path := range docutil.SortedKeys(c.base.Paths) {
+		revPathItem, exist := c.revision.Paths[path]
+		if !exist || revPathItem == nil {
+			c.add(Breaking, "path-removed", operation{path: path}, "", "path removed")
+			continue
+		}
+
+		basePathItem := c.base.Paths[path]
+		if basePathItem == nil {
+			continue
+		}
+
+		baseOperations := basePathItem.Operations()
+		revOperations := revPathItem.Operations()
+		for _, method := range docutil.SortedKeys(baseOperations) {
+			op := operation{method: method, path: path}
+			revOp, exist := revOperations[method]
+			if !exist {
+				c.add(Breaking, "operation-removed", op, "", "operation removed")
+				continue
+			}
+
+			c.compareOperation(op, baseOperations[method], revOp)
+		}
+
+		for _, method := range docutil.SortedKeys(revOperations) {
+			if _, exist := baseOperations[method]; !exist {
+				c.add(NonBreaking, "operation-added", operation{method: method, path: path}, "", "operation added")
+			}
+		}
+	}
+
+	for _, path := range docutil.SortedKeys(c.revision.Paths) {
+		if _, exist := c.base.Paths[path]; !exist {
+			c.add(NonBreaking, "path-added", operation{path: path}, "", "path added")
+		}
+	}
+}
+
+func (c *comparer) compareOperation(op operation, base, rev *openapi3.Operation) {
+	c.compareParameters(op, base.Parameters, rev.Parameters)
+	c.compareRequestBody(op, base.RequestBody, rev.RequestBody)
+	c.compareResponses(op, base.Responses, rev.Responses)
+}
+
+func (c *comparer) compareParameters(op operation, base, rev openapi3.Parameters) {
+	// parameters map k = in.name, v = the parameter
+	baseParams := make(map[string]*openapi3.Parameter)
+	for _, paramRef := range base {
+		if param := resolveParameter(c.base, paramRef); param != nil {
+			baseParams[fmt.Sprintf("%s.%s", param.In, param.Name)] = param
+		}
+	}
+
+	revParams := make(map[string]*openapi3.Parameter)
+	for _, paramRef := range rev {
+		if param := resolveParameter(c.revision, paramRef); param != nil {
+			revParams[fmt.Sprintf("%s.%s", param.In, param.Name)] = param
+		}
+	}
+
+	for _, key := range docutil.SortedKeys(baseParams) {
+		baseParam := baseParams[key]
+		location := fmt.Sprintf("%s parameter %s", baseParam.In, baseParam.Name)
+
+		revParam, exist := revParams[key]
+		if !exist {
+			c.add(NonBreaking, "parameter-removed", op, location, "parameter removed")
+			continue
+		}
+
+		if !baseParam.Required && revParam.Required {
+			c.add(Breaking, "parameter-became-required", op, location, "parameter became required")
+		}
+
+		c.compareSchema(op, location, baseParam.Schema, revParam.Schema, directionRequest, map[[2]*openapi3.Schema]bool{})
+	}
+
+	for _, key := range docutil.SortedKeys(revParams) {
+		if _, exist := baseParams[key]; exist {
+			continue
+		}
+
+		revParam := revParams[key]
+		location := fmt.Sprintf("%s parameter %s", revParam.In, revParam.Name)
+		if revParam.Required {
+			c.add(Breaking, "required-parameter-added", op, location, "required parameter added")
+			continue
+		}
+
+		c.add(NonBreaking, "parameter-added", op, location, "optional parameter added")
+	}
+}
+
+func (c *comparer) compareRequestBody(op operation, base, rev *openapi3.RequestBodyRef) {
+	baseBody := resolveRequestBody(c.base, base)
+	revBody := resolveRequestBody(c.revision, rev)
+
+	switch {
+	case baseBody == nil && revBody == nil:
+		return
+
+	case baseBody == nil:
+		if revBody.Required {
+			c.add(Breaking, "request-body-added", op, "request body", "required request body added")
+			return
+		}
+
+		c.add(NonBreaking, "request-body-added", op, "request body", "optional request body added")
+		return
+
+	case revBody == nil:
+		c.add(NonBreaking, "request-body-removed", op, "request body", "request body removed")
+		return
+	}
+
+	if !baseBody.Required && revBody.Required {
+		c.add(Breaking, "request-body-became-required", op, "request body", "request body became required")
+	}
+
+	c.compareContent(op, "request body", baseBody.Content, revBody.Content, directionRequest)
+}
+
+func (c *comparer) compareResponses(op operation, base, rev openapi3.Responses) {
+	for _, status := range docutil.SortedKeys(base) {
+		location := fmt.Sprintf("response %s", status)
+
+		revResp, exist := rev[status]
+		if !exist {
+			c.add(Breaking, "response-removed", op, location, "response status removed")
+			continue
+		}
+
+		baseResponse := resolveResponse(c.base, base[status])
+		revResponse := resolveResponse(c.revision, revResp)
+		if baseResponse == nil || revResponse == nil {
+			continue
+		}
+
+		c.compareContent(op, location, baseResponse.Content, revResponse.Content, directionResponse)
+	}
+
+	for _, status := range docutil.SortedKeys(rev) {
+		if _, exist := base[status]; !exist {
+			c.add(NonBreaking, "response-added", op, fmt.Sprintf("response %s", status), "response status added")
+		}
+	}
+}
+
+func (c *comparer) compareContent(op operation, location string, base, rev openapi3.Content, dir direction) {
+	for _, contentType := range docutil.SortedKeys(base) {
+		contentLocation := fmt.Sprintf("%s %s", location, contentType)
+
+		revMediaType, exist := rev[contentType]
+		if !exist {
+			c.add(Breaking, "content-type-removed", op, contentLocation, "content type removed")
+			continue
+		}
+
+		baseMediaType := base[contentType]
+		if baseMediaType == nil || revMediaType == nil {
+			continue
+		}
+
+		c.compareSchema(op, contentLocation, baseMediaType.Schema, revMediaType.Schema, dir, map[[2]*openapi3.Schema]bool{})
+	}
+
+	for _, contentType := range docutil.SortedKeys(rev) {
+		if _, exist := base[contentType]; !exist {
+			c.add(NonBreaking, "content-type-added", op, fmt.Sprintf("%s %s", location, contentType), "content type added")
+		}
+	}
+}
+
+// compareSchema compares the schema recursively.
+// visited is used to stop the recursion on the recursive schema.
+func (c *comparer) compareSchema(
+	op operation,
+	location string,
+	baseRef, revRef *openapi3.SchemaRef,
+	dir direction,
+	visited map[[2]*openapi3.Schema]bool,
+) {
+	base := resolveSchema(c.base, baseRef)
+	rev := resolveSchema(c.revision, revRef)
+	if base == nil || rev == nil {
+		return
+	}
+
+	if visited[[2]*openapi3.Schema{base, rev}] {
+		return
+	}
+
+	visited[[2]*openapi3.Schema{base, rev}] = true
+
+	if base.Type != rev.Type {
+		c.add(Breaking, "type-changed", op, location, "type changed from '%s' to '%s'", base.Type, rev.Type)
+		return
+	}
+
+	if base.Format != rev.Format {
+		c.add(Breaking, "format-changed", op, location, "format changed from '%s' to '%s'", base.Format, rev.Format)
+	}
+
+	c.compareEnum(op, location, base.Enum, rev.Enum, dir)
+
+	baseRequired := toSet(base.Required)
+	revRequired := toSet(rev.Required)
+	for _, name := range rev.Required {
+		if _, exist := baseRequired[name]; exist {
+			continue
+		}
+
+		if dir == directionRequest {
+			c.add(Breaking, "required-property-added", op, location, "property '%s' became required", name)
+			continue
+		}
+
+		c.add(NonBreaking, "required-property-added", op, location, "property '%s' became required", name)
+	}
+
+	for _, name := range base.Required {
+		if _, exist := revRequired[name]; exist {
+			continue
+		}
+
+		if dir == directionResponse {
+			c.add(Breaking, "required-property-removed", op, location, "property '%s' is no longer required", name)
+			continue
+		}
+
+		c.add(NonBreaking, "required-property-removed", op, location, "property '%s' is no longer required", name)
+	}
+
+	for _, name := range docutil.SortedKeys(base.Properties) {
+		revProp, exist := rev.Properties[name]
+		if !exist {
+			if dir == directionResponse {
+				c.add(Breaking, "property-removed", op, location, "property '%s' removed", name)
+				continue
+			}
+
+			c.add(NonBreaking, "property-removed", op, location, "property '%s' removed", name)
+			continue
+		}
+
+		c.compareSchema(op, fmt.Sprintf("%s/%s", location, name), base.Properties[name], revProp, dir, visited)
+	}
+
+	for _, name := range docutil.SortedKeys(rev.Properties) {
+		if _, exist := base.Properties[name]; !exist {
+			c.add(NonBreaking, "property-added", op, location, "property '%s' added", name)
+		}
+	}
+
+	if base.Items != nil && rev.Items != nil {
+		c.compareSchema(op, fmt.Sprintf("%s[]", location), base.Items, rev.Items, dir, visited)
+	}
+
+	// map value, i.e: map[string]Pet
+	if base.AdditionalProperties != nil && rev.AdditionalProperties != nil {
+		c.compareSchema(op, fmt.Sprintf("%s{}", location), base.AdditionalProperties, rev.AdditionalProperties, dir, visited)
+	}
+
+	// removed allOf schema is breaking in response because its properties is removed,
+	// and removed anyOf or oneOf schema is breaking in request because the value is not accepted anymore.
+	c.compareSchemas(op, location, "allOf", base.AllOf, rev.AllOf, dir, directionResponse, visited)
+	c.compareSchemas(op, location, "anyOf", base.AnyOf, rev.AnyOf, dir, directionRequest, visited)
+	c.compareSchemas(op, location, "oneOf", base.OneOf, rev.OneOf, dir, directionRequest, visited)
+}
+
+// compareSchemas compares the schemas of allOf, anyOf or oneOf keyword.
+// The schema is paired with the schema of the same reference, and the schema without reference is paired by index.
+// Removed schema is breaking in the removedBreaking direction, and added schema is breaking in the other direction.
+func (c *comparer) compareSchemas(
+	op operation,
+	location, keyword string,
+	base, rev openapi3.SchemaRefs,
+	dir, removedBreaking direction,
+	visited map[[2]*openapi3.Schema]bool,
+) {
+	removedLevel, addedLevel := NonBreaking, Breaking
+	if dir == removedBreaking {
+		removedLevel, addedLevel = Breaking, NonBreaking
+	}
+
+	paired := make(map[int]bool)
+	pair := func(baseRef *openapi3.SchemaRef, baseIdx int) int {
+		for revIdx, revRef := range rev {
+			if paired[revIdx] || revRef == nil {
+				continue
+			}
+
+			if baseRef.Ref != "" && baseRef.Ref == revRef.Ref {
+				return revIdx
+			}
+		}
+
+		if baseRef.Ref == "" && baseIdx < len(rev) && !paired[baseIdx] && rev[baseIdx] != nil && rev[baseIdx].Ref == "" {
+			return baseIdx
+		}
+
+		return -1
+	}
+
+	for baseIdx, baseRef := range base {
+		if baseRef == nil {
+			continue
+		}
+
+		schemaLocation := fmt.Sprintf("%s/%s[%d]", location, keyword, baseIdx)
+		revIdx := pair(baseRef, baseIdx)
+		if revIdx < 0 {
+			c.add(removedLevel, keyword+"-schema-removed", op, schemaLocation, "%s schema %s removed", keyword, schemaName(baseRef))
+			continue
+		}
+
+		paired[revIdx] = true
+		c.compareSchema(op, schemaLocation, baseRef, rev[revIdx], dir, visited)
+	}
+
+	for revIdx, revRef := range rev {
+		if paired[revIdx] || revRef == nil {
+			continue
+		}
+
+		schemaLocation := fmt.Sprintf("%s/%s[%d]", location, keyword, revIdx)
+		c.add(addedLevel, keyword+"-schema-added", op, schemaLocation, "%s schema %s added", keyword, schemaName(revRef))
+	}
+}
+
+// schemaName returns the reference of the schema, or its type if it is not a reference.
+func schemaName(ref *openapi3.SchemaRef) string {
+	if ref.Ref != "" {
+		return fmt.Sprintf("'%s'", ref.Ref)
+	}
+
+	if ref.Value != nil && ref.Value.Type != "" {
+		return fmt.Sprintf("of type '%s'", ref.Value.Type)
+	}
+
+	return "without type"
+}
+
+// compareEnum detects narrowed enum (value removed) and widened enum (value added).
+// Narrowed enum is breaking in request, and widened enum is breaking in response.
+func (c *comparer) compareEnum(op operation, location string, base, rev []interface{}, dir direction) {
+	baseEnum := make(map[string]struct{})
+	for _, v := range base {
+		baseEnum[fmt.Sprint(v)] = struct{}{}
+	}
+
+	revEnum := make(map[string]struct{})
+	for _, v := range rev {
+		revEnum[fmt.Sprint(v)] = struct{}{}
+	}
+
+	narrowLevel, widenLevel := Breaking, NonBreaking
+	if dir == directionResponse {
+		narrowLevel, widenLevel = NonBreaking, Breaking
+	}
+
+	switch {
+	case len(base) <= 0 && len(rev) <= 0:
+		return
+
+	case len(base) <= 0:
+		c.add(narrowLevel, "enum-narrowed", op, location, "enum added: %s", joinEnum(rev))
+		return
+
+	case len(rev) <= 0:
+		c.add(widenLevel, "enum-widened", op, location, "enum removed")
+		return
+	}
+
+	removed := make([]interface{}, 0)
+	for _, v := range base {
+		if _, exist := revEnum[fmt.Sprint(v)]; !exist {
+			removed = append(removed, v)
+		}
+	}
+
+	added := make([]interface{}, 0)
+	for _, v := range rev {
+		if _, exist := baseEnum[fmt.Sprint(v)]; !exist {
+			added = append(added, v)
+		}
+	}
+
+	if len(removed) > 0 {
+		c.add(narrowLevel, "enum-narrowed", op, location, "enum values removed: %s", joinEnum(removed))
+	}
+
+	if len(added) > 0 {
+		c.add(widenLevel, "enum-widened", op, location, "enum values added: %s", joinEnum(added))
+	}
+}
+
+func joinEnum(values []interface{}) string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		s = append(s, fmt.Sprintf("'%v'", v))
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+
+	return set
+}
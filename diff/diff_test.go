@@ -0,0 +1,195 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/diff"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+	"testing"
+)
+
+type PetV1 struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind" openapi3:"desc:kind of pet"`
+}
+
+type PetV2 struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type PetCreateV2 struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func generate(t *testing.T, reg *openapidoc.Registry) *openapi3.T {
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+	return doc
+}
+
+func newBase() *openapidoc.Registry {
+	base := openapidoc.NewRegistry()
+	base.Add(http.MethodPost, "/pets",
+		request.NewRequest().Body("application/json", PetV1{}, request.WithSchemaName("PetCreate")),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", PetV1{}, response.WithSchemaName("Pet")),
+			"422": response.NewResponse().Body("application/json", PetV1{}, response.WithSchemaName("Pet")),
+		},
+	)
+	base.Add(http.MethodDelete, "/pets/{id}",
+		request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+		map[string]*response.Response{"204": response.NewResponse()},
+	)
+
+	return base
+}
+
+func TestCompare(t *testing.T) {
+	revision := openapidoc.NewRegistry()
+	revision.Add(http.MethodPost, "/pets",
+		request.NewRequest().Body("application/json", PetCreateV2{}, request.WithSchemaName("PetCreate")),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", PetV2{}, response.WithSchemaName("Pet")),
+		},
+	)
+	revision.Add(http.MethodGet, "/pets",
+		request.NewRequest(),
+		map[string]*response.Response{"200": response.NewResponse().Body("application/json", []PetV2{})},
+	)
+
+	baseDoc := generate(t, newBase())
+	revDoc := generate(t, revision)
+
+	// new required property in the request is breaking
+	revDoc.Components.Schemas["PetCreate"].Value.Required = []string{"age"}
+
+	result := diff.Compare(baseDoc, revDoc)
+	assert.True(t, result.HasBreaking())
+
+	kinds := make(map[string]diff.Level)
+	for _, change := range result.Changes {
+		kinds[change.Kind] = change.Level
+	}
+
+	assert.Equal(t, diff.Breaking, kinds["path-removed"])
+	assert.Equal(t, diff.Breaking, kinds["response-removed"])
+	assert.Equal(t, diff.Breaking, kinds["type-changed"])
+	assert.Equal(t, diff.Breaking, kinds["required-property-added"])
+	assert.Equal(t, diff.Breaking, kinds["property-removed"])
+	assert.Equal(t, diff.NonBreaking, kinds["operation-added"])
+	assert.Equal(t, diff.NonBreaking, kinds["property-added"])
+
+	t.Run("enum", func(t *testing.T) {
+		baseDoc := generate(t, newBase())
+		revDoc := generate(t, newBase())
+		baseDoc.Components.Schemas["PetCreate"].Value.Properties["kind"].Value.Enum = []interface{}{"cat", "dog"}
+		revDoc.Components.Schemas["PetCreate"].Value.Properties["kind"].Value.Enum = []interface{}{"cat"}
+
+		result := diff.Compare(baseDoc, revDoc)
+		assert.Len(t, result.Changes, 1)
+		assert.Equal(t, "enum-narrowed", result.Changes[0].Kind)
+		assert.Equal(t, diff.Breaking, result.Changes[0].Level)
+	})
+
+	t.Run("map value", func(t *testing.T) {
+		newRegistry := func(body interface{}) *openapidoc.Registry {
+			reg := openapidoc.NewRegistry()
+			reg.Add(http.MethodGet, "/owners",
+				request.NewRequest(),
+				map[string]*response.Response{
+					"200": response.NewResponse().Body("application/json", body, response.WithSchemaName("Owners")),
+				},
+			)
+
+			return reg
+		}
+
+		result := diff.Compare(
+			generate(t, newRegistry(struct {
+				Pets map[string]PetV1 `json:"pets"`
+			}{})),
+			generate(t, newRegistry(struct {
+				Pets map[string]PetV2 `json:"pets"`
+			}{})),
+		)
+
+		assert.True(t, result.HasBreaking())
+		locations := make(map[string]string)
+		for _, change := range result.Breaking() {
+			locations[change.Kind] = change.Location
+		}
+
+		assert.Equal(t, "response 200 application/json/pets{}/id", locations["type-changed"])
+		assert.Equal(t, "response 200 application/json/pets{}", locations["property-removed"])
+	})
+
+	t.Run("anyOf item", func(t *testing.T) {
+		baseDoc := generate(t, newBase())
+		revDoc := generate(t, newBase())
+
+		// the array of struct generated by schema.Generator
+		for _, doc := range []*openapi3.T{baseDoc, revDoc} {
+			doc.Components.Schemas["Pet"].Value.Properties["friends"] = openapi3.NewArraySchema().WithItems(&openapi3.Schema{
+				AnyOf: openapi3.SchemaRefs{{Ref: "#/components/schemas/Friend"}},
+			}).NewRef()
+		}
+
+		baseDoc.Components.Schemas["Friend"] = openapi3.NewObjectSchema().
+			WithProperty("id", openapi3.NewIntegerSchema()).
+			WithProperty("name", openapi3.NewStringSchema()).NewRef()
+		revDoc.Components.Schemas["Friend"] = openapi3.NewObjectSchema().
+			WithProperty("id", openapi3.NewStringSchema()).NewRef()
+
+		result := diff.Compare(baseDoc, revDoc)
+		assert.True(t, result.HasBreaking())
+
+		breaking := make([]string, 0)
+		for _, change := range result.Breaking() {
+			breaking = append(breaking, change.Kind+" "+change.Location)
+		}
+
+		assert.Contains(t, breaking, "type-changed response 201 application/json/friends[]/anyOf[0]/id")
+		assert.Contains(t, breaking, "property-removed response 201 application/json/friends[]/anyOf[0]")
+
+		// the removed anyOf schema is breaking in request
+		revDoc = generate(t, newBase())
+		revDoc.Components.Schemas["Pet"].Value.Properties["friends"] = openapi3.NewArraySchema().WithItems(&openapi3.Schema{}).NewRef()
+		baseDoc.Components.Schemas["PetCreate"].Value.Properties["friends"] = baseDoc.Components.Schemas["Pet"].Value.Properties["friends"]
+		revDoc.Components.Schemas["PetCreate"].Value.Properties["friends"] = revDoc.Components.Schemas["Pet"].Value.Properties["friends"]
+
+		levels := make(map[string]diff.Level)
+		for _, change := range diff.Compare(baseDoc, revDoc).Changes {
+			levels[change.Location] = change.Level
+		}
+
+		assert.Equal(t, diff.Breaking, levels["request body application/json/friends[]/anyOf[0]"])
+		assert.Equal(t, diff.NonBreaking, levels["response 201 application/json/friends[]/anyOf[0]"])
+	})
+
+	t.Run("output", func(t *testing.T) {
+		b, err := result.JSON()
+		assert.NoError(t, err)
+
+		var out diff.Result
+		assert.NoError(t, json.Unmarshal(b, &out))
+		assert.Equal(t, result, out)
+
+		md := result.Markdown()
+		assert.Contains(t, md, "## Breaking changes")
+		assert.Contains(t, md, "## Non-breaking changes")
+		assert.Contains(t, md, "`/pets/{id}` path removed")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		result := diff.Compare(generate(t, newBase()), generate(t, newBase()))
+		assert.False(t, result.HasBreaking())
+		assert.Empty(t, result.Changes)
+	})
+}
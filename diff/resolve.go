@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"strings"
+)
+
+// maxRefDepth limits the reference lookup, so the reference cycle (a refer to b, b refer to a) is not looping forever.
+const maxRefDepth = 32
+
+// refName returns the component name from local reference, i.e: #/components/schemas/Pet returns Pet.
+func refName(ref, prefix string) (string, bool) {
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(ref, prefix), true
+}
+
+// resolveSchema returns the schema value, look up to the document components if it is a reference.
+// Document that loaded using openapi3.Loader already has the value, but document from Registry.Generate only has the reference.
+func resolveSchema(doc *openapi3.T, schemaRef *openapi3.SchemaRef) *openapi3.Schema {
+	for i := 0; schemaRef != nil && i < maxRefDepth; i++ {
+		if schemaRef.Value != nil {
+			return schemaRef.Value
+		}
+
+		name, ok := refName(schemaRef.Ref, "#/components/schemas/")
+		if !ok {
+			return nil
+		}
+
+		schemaRef = doc.Components.Schemas[name]
+	}
+
+	return nil
+}
+
+// resolveParameter returns the parameter value, look up to the document components if it is a reference.
+func resolveParameter(doc *openapi3.T, paramRef *openapi3.ParameterRef) *openapi3.Parameter {
+	for i := 0; paramRef != nil && i < maxRefDepth; i++ {
+		if paramRef.Value != nil {
+			return paramRef.Value
+		}
+
+		name, ok := refName(paramRef.Ref, "#/components/parameters/")
+		if !ok {
+			return nil
+		}
+
+		paramRef = doc.Components.Parameters[name]
+	}
+
+	return nil
+}
+
+// resolveRequestBody returns the request body value, look up to the document components if it is a reference.
+func resolveRequestBody(doc *openapi3.T, bodyRef *openapi3.RequestBodyRef) *openapi3.RequestBody {
+	for i := 0; bodyRef != nil && i < maxRefDepth; i++ {
+		if bodyRef.Value != nil {
+			return bodyRef.Value
+		}
+
+		name, ok := refName(bodyRef.Ref, "#/components/requestBodies/")
+		if !ok {
+			return nil
+		}
+
+		bodyRef = doc.Components.RequestBodies[name]
+	}
+
+	return nil
+}
+
+// resolveResponse returns the response value, look up to the document components if it is a reference.
+func resolveResponse(doc *openapi3.T, respRef *openapi3.ResponseRef) *openapi3.Response {
+	for i := 0; respRef != nil && i < maxRefDepth; i++ {
+		if respRef.Value != nil {
+			return respRef.Value
+		}
+
+		name, ok := refName(respRef.Ref, "#/components/responses/")
+		if !ok {
+			return nil
+		}
+
+		respRef = doc.Components.Responses[name]
+	}
+
+	return nil
+}
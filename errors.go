@@ -0,0 +1,66 @@
+package openapidoc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+)
+
+var (
+	// ErrDuplicateRoute is returned when the same method and path is added again using DuplicateRouteError policy.
+	ErrDuplicateRoute = errors.New("route is already added")
+
+	// ErrRouteNotFound is returned when replacing the method and path which is not added yet.
+	ErrRouteNotFound = errors.New("route is not found")
+)
+
+// AddError is the error of Registry.Add with the context where the error happens.
+// Use errors.As to get it from the error returned by TryAdd or Generate.
+type AddError struct {
+	Method string
+	Path   string
+
+	// Status is the http status of the failed response, empty if the error is in the request
+	Status string
+
+	// ContentType, GoType, FieldPath and Struct is filled when the error happens when generating the body schema,
+	// i.e: application/json, main.Pet, Owner.Address.City and main.Address
+	ContentType string
+	GoType      string
+	FieldPath   string
+	Struct      string
+
+	Err error
+}
+
+func newAddError(method, path, status string, err error) *AddError {
+	addErr := &AddError{
+		Method: method,
+		Path:   path,
+		Status: status,
+		Err:    err,
+	}
+
+	var schemaErr *utils.SchemaError
+	if errors.As(err, &schemaErr) {
+		addErr.ContentType = schemaErr.ContentType
+		addErr.GoType = schemaErr.GoType
+		addErr.FieldPath = schemaErr.FieldPath
+		addErr.Struct = schemaErr.Struct
+	}
+
+	return addErr
+}
+
+// Error returns the message with the method, path and status, the content type, Go type and field path is written by the wrapped error.
+func (e *AddError) Error() string {
+	if e.Status != "" {
+		return fmt.Sprintf("%s %s response %s: %s", e.Method, e.Path, e.Status, e.Err)
+	}
+
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Path, e.Err)
+}
+
+func (e *AddError) Unwrap() error {
+	return e.Err
+}
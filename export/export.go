@@ -0,0 +1,290 @@
+// Package export writes the OpenAPI document as the request collections used by the API clients,
+// such as Postman collection v2.1 and .http request files.
+//
+// Requests are grouped by the first operation tag, path params, query params, headers and body are pre-filled
+// using the documented examples, and the server URLs are written as variables.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"sort"
+	"strings"
+)
+
+// variable is the server URL variable, i.e: baseUrl = https://example.com
+type variable struct {
+	Name        string
+	Value       string
+	Description string
+}
+
+// keyValue is the pre-filled path param, query param or header.
+type keyValue struct {
+	Key         string
+	Value       string
+	Description string
+}
+
+// operation is the pre-filled request of one method and path.
+type operation struct {
+	Name        string
+	Description string
+	Method      string
+	Path        string // documented path template, i.e: /pets/{id}
+	PathParams  []keyValue
+	QueryParams []keyValue
+	Headers     []keyValue
+	ContentType string
+	Body        string
+}
+
+// group is the operations with the same tag, empty tag for the operation without tag.
+type group struct {
+	Tag        string
+	Operations []operation
+}
+
+// collection is the intermediate model shared by all formats.
+type collection struct {
+	Name        string
+	Description string
+	Variables   []variable
+	Groups      []group
+}
+
+// resolve marshal and load the document again, so all references value is resolved.
+func resolve(doc *openapi3.T) (*openapi3.T, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("document must not be nil")
+	}
+
+	docJSON, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = openapi3.NewLoader().LoadFromData(docJSON)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// newCollection builds the collection from the document, groups and operations are sorted, so the output is deterministic.
+func newCollection(doc *openapi3.T) (*collection, error) {
+	doc, err := resolve(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &collection{}
+	if doc.Info != nil {
+		c.Name = doc.Info.Title
+		c.Description = doc.Info.Description
+	}
+
+	if c.Name == "" {
+		c.Name = "API"
+	}
+
+	for i, server := range doc.Servers {
+		if server == nil {
+			continue
+		}
+
+		name := "baseUrl"
+		if i > 0 {
+			name = fmt.Sprintf("baseUrl%d", i+1)
+		}
+
+		c.Variables = append(c.Variables, variable{
+			Name:        name,
+			Value:       strings.TrimSuffix(serverURL(server), "/"),
+			Description: server.Description,
+		})
+	}
+
+	// groups map k = tag, v = index in c.Groups
+	groups := make(map[string]int)
+	for _, path := range docutil.SortedKeys(doc.Paths) {
+		pathItem := doc.Paths[path]
+		operations := pathItem.Operations()
+		for _, method := range docutil.SortedKeys(operations) {
+			op := newOperation(method, path, pathItem, operations[method])
+
+			tag := ""
+			if len(operations[method].Tags) > 0 {
+				tag = operations[method].Tags[0]
+			}
+
+			idx, exist := groups[tag]
+			if !exist {
+				idx = len(c.Groups)
+				groups[tag] = idx
+				c.Groups = append(c.Groups, group{Tag: tag})
+			}
+
+			c.Groups[idx].Operations = append(c.Groups[idx].Operations, op)
+		}
+	}
+
+	// untagged operations are written first, then sorted by tag name
+	sort.SliceStable(c.Groups, func(i, j int) bool {
+		return c.Groups[i].Tag < c.Groups[j].Tag
+	})
+
+	return c, nil
+}
+
+func newOperation(method, path string, pathItem *openapi3.PathItem, op *openapi3.Operation) operation {
+	out := operation{
+		Name:        op.Summary,
+		Description: op.Description,
+		Method:      method,
+		Path:        path,
+	}
+
+	if out.Name == "" {
+		out.Name = op.OperationID
+	}
+
+	if out.Name == "" {
+		out.Name = method + " " + path
+	}
+
+	// operation parameters override the path item parameters with the same location and name
+	params := make(map[string]*openapi3.Parameter)
+	for _, paramRefs := range []openapi3.Parameters{pathItem.Parameters, op.Parameters} {
+		for _, paramRef := range paramRefs {
+			if paramRef == nil || paramRef.Value == nil {
+				continue
+			}
+
+			params[paramRef.Value.In+"."+paramRef.Value.Name] = paramRef.Value
+		}
+	}
+
+	for _, key := range docutil.SortedKeys(params) {
+		param := params[key]
+		kv := keyValue{
+			Key:         param.Name,
+			Value:       parameterExample(param),
+			Description: param.Description,
+		}
+
+		switch param.In {
+		case openapi3.ParameterInPath:
+			out.PathParams = append(out.PathParams, kv)
+		case openapi3.ParameterInQuery:
+			out.QueryParams = append(out.QueryParams, kv)
+		case openapi3.ParameterInHeader:
+			out.Headers = append(out.Headers, kv)
+		}
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		out.ContentType, out.Body = requestBody(op.RequestBody.Value.Content)
+	}
+
+	return out
+}
+
+// serverURL returns the server URL with the variables replaced by the default value.
+func serverURL(server *openapi3.Server) string {
+	url := server.URL
+	for name, v := range server.Variables {
+		if v != nil {
+			url = strings.ReplaceAll(url, "{"+name+"}", v.Default)
+		}
+	}
+
+	return url
+}
+
+func parameterExample(param *openapi3.Parameter) string {
+	if param.Example != nil {
+		return stringValue(param.Example)
+	}
+
+	if names := sortedExampleNames(param.Examples); len(names) > 0 {
+		return stringValue(param.Examples[names[0]].Value.Value)
+	}
+
+	if param.Schema != nil {
+		if example := utils.SchemaExample(param.Schema.Value); example != nil {
+			return stringValue(example)
+		}
+	}
+
+	return ""
+}
+
+// requestBody returns the JSON content type if exist, otherwise the first sorted content type,
+// and its example body: the first sorted named example, media type example, or the example built from the schema.
+func requestBody(content openapi3.Content) (contentType string, body string) {
+	contentType = docutil.PreferredContentType(content)
+	mediaType := content[contentType]
+	if mediaType == nil {
+		return contentType, ""
+	}
+
+	var example interface{}
+	if names := sortedExampleNames(mediaType.Examples); len(names) > 0 {
+		example = mediaType.Examples[names[0]].Value.Value
+	} else if mediaType.Example != nil {
+		example = mediaType.Example
+	} else if mediaType.Schema != nil {
+		example = utils.SchemaExample(mediaType.Schema.Value)
+	}
+
+	if example == nil {
+		return contentType, ""
+	}
+
+	if str, ok := example.(string); ok && !docutil.IsJSON(contentType) {
+		return contentType, str
+	}
+
+	b, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return contentType, fmt.Sprint(example)
+	}
+
+	return contentType, string(b)
+}
+
+// sortedExampleNames returns the name of resolved examples sorted by name.
+func sortedExampleNames(examples openapi3.Examples) []string {
+	names := make([]string, 0, len(examples))
+	for name, exampleRef := range examples {
+		if exampleRef != nil && exampleRef.Value != nil {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func stringValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	case float64, float32, int, int64, int32, bool:
+		return fmt.Sprint(v)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,124 @@
+package export_test
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/export"
+	"github.com/yusufsyaifudin/openapidoc/header"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+	"testing"
+)
+
+type Pet struct {
+	ID   int    `json:"id" openapi3:"ex:2"`
+	Name string `json:"name"`
+}
+
+func newDocument(t *testing.T) *openapi3.T {
+	reg := openapidoc.NewRegistry(
+		openapidoc.WithServerInfo(&openapi3.Info{Title: "Pet Store", Version: "v1.0.0"}),
+		openapidoc.WithServers(openapi3.Servers{
+			{URL: "https://api.example.com/"},
+			{URL: "http://localhost:{port}", Variables: map[string]*openapi3.ServerVariable{"port": {Default: "8080"}}},
+		}),
+	)
+
+	reg.Add(http.MethodPost, "/pets",
+		request.NewRequest().
+			Header(header.NewHeader().Add("X-Request-Id", header.Map{Value: "abc"})).
+			Body("application/json", Pet{}, request.WithExamples(
+				request.Example{Name: "kitty", Value: Pet{ID: 1, Name: "Kitty"}},
+			)),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", Pet{}),
+		},
+		openapidoc.WithOperationID("createPet"),
+		openapidoc.WithOperationTags("pets"),
+	)
+
+	reg.Add(http.MethodGet, "/pets/{id}",
+		request.NewRequest().
+			PathParams(request.PathParam{Name: "id", Value: 7}).
+			QueryParams(request.QueryParam{Name: "fields", Value: "id,name"}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Pet{}),
+		},
+		openapidoc.WithOperationID("getPet"),
+		openapidoc.WithOperationTags("pets"),
+	)
+
+	reg.Add(http.MethodGet, "/health",
+		request.NewRequest(),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("text/plain", "OK"),
+		},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+	return doc
+}
+
+func TestPostman(t *testing.T) {
+	b, err := export.Postman(newDocument(t))
+	assert.NoError(t, err)
+
+	var collection struct {
+		Info struct {
+			Name   string `json:"name"`
+			Schema string `json:"schema"`
+		} `json:"info"`
+		Item []struct {
+			Name    string `json:"name"`
+			Item    []json.RawMessage
+			Request *struct {
+				Method string `json:"method"`
+			} `json:"request"`
+		} `json:"item"`
+		Variable []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"variable"`
+	}
+
+	assert.NoError(t, json.Unmarshal(b, &collection))
+	assert.Equal(t, "Pet Store", collection.Info.Name)
+	assert.Contains(t, collection.Info.Schema, "v2.1.0")
+
+	assert.Len(t, collection.Variable, 2)
+	assert.Equal(t, "baseUrl", collection.Variable[0].Key)
+	assert.Equal(t, "https://api.example.com", collection.Variable[0].Value)
+	assert.Equal(t, "baseUrl2", collection.Variable[1].Key)
+	assert.Equal(t, "http://localhost:8080", collection.Variable[1].Value)
+
+	// untagged operation is in the root, tagged operation is in the folder
+	assert.Len(t, collection.Item, 2)
+	assert.NotNil(t, collection.Item[0].Request)
+	assert.Equal(t, http.MethodGet, collection.Item[0].Request.Method)
+	assert.Equal(t, "pets", collection.Item[1].Name)
+	assert.Len(t, collection.Item[1].Item, 2)
+
+	assert.Contains(t, string(b), `"raw": "{{baseUrl}}/pets/:id?fields=id,name"`)
+	assert.Contains(t, string(b), `"key": "id",`)
+	assert.Contains(t, string(b), `"value": "7"`)
+	assert.Contains(t, string(b), `"key": "X-Request-Id",`)
+	assert.Contains(t, string(b), `\"name\": \"Kitty\"`)
+}
+
+func TestHTTPFile(t *testing.T) {
+	b, err := export.HTTPFile(newDocument(t))
+	assert.NoError(t, err)
+
+	out := string(b)
+	assert.Contains(t, out, "# Pet Store\n")
+	assert.Contains(t, out, "@baseUrl = https://api.example.com\n")
+	assert.Contains(t, out, "@baseUrl2 = http://localhost:8080\n")
+	assert.Contains(t, out, "### GET /health\nGET {{baseUrl}}/health\n")
+	assert.Contains(t, out, "#\n# pets\n#\n")
+	assert.Contains(t, out, "### createPet\nPOST {{baseUrl}}/pets\nX-Request-Id: abc\nContent-Type: application/json\n\n{\n  \"id\": 1,\n  \"name\": \"Kitty\"\n}\n")
+	assert.Contains(t, out, "### getPet\nGET {{baseUrl}}/pets/7?fields=id%2Cname\n")
+}
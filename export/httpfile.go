@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"net/url"
+	"strings"
+)
+
+// HTTPFile returns the document as .http request file, supported by JetBrains HTTP Client and VS Code REST Client.
+// Server URLs are written as file variables, i.e: @baseUrl = https://example.com
+// Operations are grouped by the first tag, and each request is separated by ### line with the operation name.
+func HTTPFile(doc *openapi3.T) ([]byte, error) {
+	c, err := newCollection(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	_, _ = fmt.Fprintf(buf, "# %s\n", c.Name)
+	for _, line := range nonEmptyLines(c.Description) {
+		_, _ = fmt.Fprintf(buf, "# %s\n", line)
+	}
+
+	if len(c.Variables) > 0 {
+		buf.WriteString("\n")
+	}
+
+	for _, v := range c.Variables {
+		_, _ = fmt.Fprintf(buf, "@%s = %s\n", v.Name, v.Value)
+	}
+
+	for _, g := range c.Groups {
+		if g.Tag != "" {
+			_, _ = fmt.Fprintf(buf, "\n#\n# %s\n#\n", g.Tag)
+		}
+
+		for _, op := range g.Operations {
+			writeHTTPRequest(buf, op)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeHTTPRequest(buf *bytes.Buffer, op operation) {
+	_, _ = fmt.Fprintf(buf, "\n### %s\n", op.Name)
+	for _, line := range nonEmptyLines(op.Description) {
+		_, _ = fmt.Fprintf(buf, "# %s\n", line)
+	}
+
+	pathParams := make(map[string]string, len(op.PathParams))
+	for _, param := range op.PathParams {
+		pathParams[param.Key] = param.Value
+	}
+
+	path := docutil.ExpandPath(op.Path, pathParams)
+
+	if len(op.QueryParams) > 0 {
+		query := make([]string, 0, len(op.QueryParams))
+		for _, param := range op.QueryParams {
+			query = append(query, url.QueryEscape(param.Key)+"="+url.QueryEscape(param.Value))
+		}
+
+		path += "?" + strings.Join(query, "&")
+	}
+
+	_, _ = fmt.Fprintf(buf, "%s {{baseUrl}}%s\n", op.Method, path)
+	for _, h := range op.Headers {
+		_, _ = fmt.Fprintf(buf, "%s: %s\n", h.Key, h.Value)
+	}
+
+	if op.ContentType == "" {
+		return
+	}
+
+	_, _ = fmt.Fprintf(buf, "Content-Type: %s\n", op.ContentType)
+	if op.Body != "" {
+		_, _ = fmt.Fprintf(buf, "\n%s\n", op.Body)
+	}
+}
+
+func nonEmptyLines(s string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
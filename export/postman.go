@@ -0,0 +1,164 @@
+package export
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"strings"
+)
+
+// postmanSchema is the Postman collection format version written by Postman.
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// postmanItem is either the folder (Item is not empty) or the request.
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []postmanItem   `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string       `json:"method"`
+	Header []postmanKV  `json:"header"`
+	URL    postmanURL   `json:"url"`
+	Body   *postmanBody `json:"body,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanKV       `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanKV struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Postman returns the document as Postman collection v2.1 JSON.
+// Operations are grouped into folders by the first tag, untagged operation is written in the root.
+// Server URLs are written as collection variables: baseUrl for the first server, baseUrl2 for the second, and so on.
+func Postman(doc *openapi3.T) ([]byte, error) {
+	c, err := newCollection(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	out := postmanCollection{
+		Info: postmanInfo{
+			Name:        c.Name,
+			Description: c.Description,
+			Schema:      postmanSchema,
+		},
+		Item: make([]postmanItem, 0),
+	}
+
+	for _, v := range c.Variables {
+		out.Variable = append(out.Variable, postmanVariable{Key: v.Name, Value: v.Value, Description: v.Description})
+	}
+
+	for _, g := range c.Groups {
+		items := make([]postmanItem, 0, len(g.Operations))
+		for _, op := range g.Operations {
+			items = append(items, newPostmanItem(op))
+		}
+
+		if g.Tag == "" {
+			out.Item = append(out.Item, items...)
+			continue
+		}
+
+		out.Item = append(out.Item, postmanItem{Name: g.Tag, Item: items})
+	}
+
+	return json.MarshalIndent(out, "", "  ")
+}
+
+func newPostmanItem(op operation) postmanItem {
+	// Postman uses :name as the path variable
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(strings.Trim(op.Path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segment = ":" + strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		}
+
+		segments = append(segments, segment)
+	}
+
+	req := &postmanRequest{
+		Method: op.Method,
+		Header: make([]postmanKV, 0),
+		URL: postmanURL{
+			Host: []string{"{{baseUrl}}"},
+			Path: segments,
+		},
+	}
+
+	raw := "{{baseUrl}}/" + strings.Join(segments, "/")
+	query := make([]string, 0, len(op.QueryParams))
+	for _, param := range op.QueryParams {
+		req.URL.Query = append(req.URL.Query, postmanKV{Key: param.Key, Value: param.Value, Description: param.Description})
+		query = append(query, param.Key+"="+param.Value)
+	}
+
+	if len(query) > 0 {
+		raw += "?" + strings.Join(query, "&")
+	}
+
+	req.URL.Raw = raw
+	for _, param := range op.PathParams {
+		req.URL.Variable = append(req.URL.Variable, postmanVariable{Key: param.Key, Value: param.Value, Description: param.Description})
+	}
+
+	for _, h := range op.Headers {
+		req.Header = append(req.Header, postmanKV{Key: h.Key, Value: h.Value, Description: h.Description})
+	}
+
+	if op.ContentType != "" {
+		req.Header = append(req.Header, postmanKV{Key: "Content-Type", Value: op.ContentType})
+		req.Body = &postmanBody{Mode: "raw", Raw: op.Body}
+		if docutil.IsJSON(op.ContentType) {
+			req.Body.Options = map[string]interface{}{
+				"raw": map[string]string{"language": "json"},
+			}
+		}
+	}
+
+	return postmanItem{
+		Name:        op.Name,
+		Description: op.Description,
+		Request:     req,
+	}
+}
@@ -0,0 +1,171 @@
+package openapidoc
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+)
+
+// Common audience labels, any other label can be used.
+const (
+	AudiencePublic   = "public"
+	AudiencePartner  = "partner"
+	AudienceInternal = "internal"
+)
+
+// WithOperationAudience sets the audience labels of the added method and path, i.e: public, partner or internal.
+// The label is not written in the document, it is only used to filter the document using ForAudience.
+func WithOperationAudience(audiences ...string) func(*OperationConfig) {
+	return func(o *OperationConfig) {
+		for _, audience := range audiences {
+			if audience != "" && !contains(o.audiences, audience) {
+				o.audiences = append(o.audiences, audience)
+			}
+		}
+	}
+}
+
+type generateOpt struct {
+	audiences []string
+	tags      []string
+	prune     bool
+}
+
+// ForAudience generates only the operations which has one of the audience labels.
+// Operation without audience label is not generated when this filter is used.
+// The response link to the operation which is not generated is removed.
+func ForAudience(audiences ...string) func(*generateOpt) {
+	return func(o *generateOpt) {
+		o.audiences = append(o.audiences, audiences...)
+	}
+}
+
+// ForTag generates only the operations which has one of the tags.
+// The response link to the operation which is not generated is removed.
+func ForTag(tags ...string) func(*generateOpt) {
+	return func(o *generateOpt) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+func (o *generateOpt) filtered() bool {
+	return len(o.audiences) > 0 || len(o.tags) > 0
+}
+
+// match returns true if the route matches both audience and tag filter.
+func (o *generateOpt) match(route Route) bool {
+	return matchAny(o.audiences, route.Audiences) && matchAny(o.tags, route.Tags)
+}
+
+// matchAny returns true if filter is empty or one of values is in the filter.
+func matchAny(filter, values []string) bool {
+	if len(filter) <= 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if contains(filter, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filter returns the paths with only matching operations, and the components referenced by those operations.
+// The returned path items are copied, so the registry paths are not changed.
+func (r *Registry) filter(opt *generateOpt) (openapi3.Paths, openapi3.Components) {
+	paths := make(openapi3.Paths)
+	for _, route := range r.routes {
+		if !opt.match(route) {
+			continue
+		}
+
+		pathItem := r.paths[route.Path]
+		if pathItem == nil {
+			continue
+		}
+
+		operation := pathItem.Operations()[route.Method]
+		if operation == nil {
+			continue
+		}
+
+		if paths[route.Path] == nil {
+			paths[route.Path] = &openapi3.PathItem{
+				ExtensionProps: pathItem.ExtensionProps,
+				Ref:            pathItem.Ref,
+				Summary:        pathItem.Summary,
+				Description:    pathItem.Description,
+				Servers:        pathItem.Servers,
+				Parameters:     pathItem.Parameters,
+			}
+		}
+
+		paths[route.Path].SetOperation(route.Method, operation)
+	}
+
+	values := make([]interface{}, 0, len(paths))
+	for _, pathItem := range paths {
+		values = append(values, pathItem)
+	}
+
+	components := utils.FilterComponents(r.components, utils.CollectRefs(r.components, values...))
+	if !dropFilteredLinks(paths, &components) {
+		return paths, components
+	}
+
+	// the link components which is not used anymore is removed
+	return paths, utils.FilterComponents(&components, utils.CollectRefs(&components, values...))
+}
+
+// dropFilteredLinks removes the response links which refer to the operation not in the paths,
+// so the filtered document does not have dangling link or leak the operationId of the filtered operation.
+// The changed responses are copied, so the registry components are not changed.
+// It returns true if any link is removed.
+func dropFilteredLinks(paths openapi3.Paths, components *openapi3.Components) (dropped bool) {
+	operationIDs := make(map[string]struct{})
+	for _, pathItem := range paths {
+		for _, operation := range pathItem.Operations() {
+			operationIDs[operation.OperationID] = struct{}{}
+		}
+	}
+
+	for name, respRef := range components.Responses {
+		if respRef == nil || respRef.Value == nil || len(respRef.Value.Links) <= 0 {
+			continue
+		}
+
+		links := make(openapi3.Links, len(respRef.Value.Links))
+		for linkName, linkRef := range respRef.Value.Links {
+			link := linkRef.Value
+			if linkRef.Ref != "" {
+				if component, ok := utils.ComponentByRef(components, linkRef.Ref).(*openapi3.LinkRef); ok {
+					link = component.Value
+				}
+			}
+
+			if link != nil {
+				if _, exist := operationIDs[link.OperationID]; !exist {
+					continue
+				}
+			}
+
+			links[linkName] = linkRef
+		}
+
+		if len(links) == len(respRef.Value.Links) {
+			continue
+		}
+
+		resp := *respRef.Value
+		resp.Links = links
+		if len(links) <= 0 {
+			resp.Links = nil
+		}
+
+		components.Responses[name] = &openapi3.ResponseRef{Value: &resp}
+		dropped = true
+	}
+
+	return
+}
@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/yusufsyaifudin/openapidoc/utils"
 )
 
 type Map struct {
 	Value       string
 	Description string
 	Required    bool
+
+	// Extensions is vendor extensions added both to the response header and request header parameter.
+	Extensions map[string]interface{}
 }
 
 type Header struct {
@@ -33,6 +37,16 @@ func (h *Header) Add(k string, v Map) *Header {
 	return h
 }
 
+// Values returns copy of the added header, header actual key name as the map key.
+func (h *Header) Values() map[string]Map {
+	values := make(map[string]Map, len(h.headerMap))
+	for k, v := range h.headerMap {
+		values[k] = v
+	}
+
+	return values
+}
+
 // RespHeaderRef returns header actual key name as the map key,
 // and string of referenced header as the value.
 // Components must be called before call this function, otherwise this will return empty map.
@@ -58,6 +72,12 @@ func (h *Header) Components(gen *openapi3gen.Generator) (components openapi3.Com
 	openapi3params := make(map[string]*openapi3.ParameterRef)
 
 	for key, header := range h.headerMap {
+		err = utils.ValidateExtensions(header.Extensions)
+		if err != nil {
+			err = fmt.Errorf("invalid header %s extension: %w", key, err)
+			return
+		}
+
 		// add each header to schemas with the name headerSchema.{actualHeaderKey}
 		schemaName := fmt.Sprintf("headerSchema.%s", key)
 		openapi3schema[schemaName] = &openapi3.SchemaRef{
@@ -73,7 +93,8 @@ func (h *Header) Components(gen *openapi3gen.Generator) (components openapi3.Com
 		openapi3headers[key] = &openapi3.HeaderRef{
 			Value: &openapi3.Header{
 				Parameter: openapi3.Parameter{
-					Description: header.Description,
+					ExtensionProps: openapi3.ExtensionProps{Extensions: header.Extensions},
+					Description:    header.Description,
 					Schema: &openapi3.SchemaRef{
 						Ref: fmt.Sprintf("#/components/schemas/%s", schemaName),
 					},
@@ -89,10 +110,11 @@ func (h *Header) Components(gen *openapi3gen.Generator) (components openapi3.Com
 		paramName := fmt.Sprintf("headerParam.%s", key)
 		openapi3params[paramName] = &openapi3.ParameterRef{
 			Value: &openapi3.Parameter{
-				In:          "header",
-				Name:        key,
-				Description: header.Description,
-				Required:    header.Required,
+				ExtensionProps: openapi3.ExtensionProps{Extensions: header.Extensions},
+				In:             "header",
+				Name:           key,
+				Description:    header.Description,
+				Required:       header.Required,
 				Schema: &openapi3.SchemaRef{
 					Ref: fmt.Sprintf("#/components/schemas/%s", schemaName),
 				},
@@ -0,0 +1,301 @@
+package openapidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"reflect"
+	"strings"
+)
+
+// ReferencePolicy is which components are referenced from the operations, and which are inlined.
+type ReferencePolicy int
+
+const (
+	// ReferenceAll references every request body, response, header, parameter and schema from the components.
+	ReferenceAll ReferencePolicy = iota
+
+	// ReferenceNamedStructs only references the body schema of named Go struct, i.e: main.Pet,
+	// the other request bodies, responses, headers, parameters and schemas are inlined.
+	ReferenceNamedStructs
+
+	// InlineAll inlines every request body, response, header, parameter and schema.
+	// The recursive schema is still referenced, because it cannot be inlined.
+	InlineAll
+)
+
+// WithReferencePolicy sets which components are inlined into the operations, by default ReferenceAll.
+// Examples, links and security schemes are always referenced.
+func WithReferencePolicy(policy ReferencePolicy) func(*Config) {
+	return func(config *Config) {
+		config.referencePolicy = policy
+	}
+}
+
+// inline returns the paths and components with the references inlined using the policy.
+// The inlined components which are not referenced anymore is removed, the registry paths and components are not changed.
+func (r *Registry) inline(paths openapi3.Paths, components openapi3.Components, policy ReferencePolicy) (openapi3.Paths, openapi3.Components, error) {
+	if policy == ReferenceAll {
+		return paths, components, nil
+	}
+
+	keep := make(map[string]struct{})
+	if policy == ReferenceNamedStructs {
+		keep = r.structSchemaRefs(paths, &components)
+	}
+
+	componentsJSON, err := toJSONValue(components)
+	if err != nil {
+		return nil, openapi3.Components{}, fmt.Errorf("cannot inline components: %w", err)
+	}
+
+	pathsJSON, err := toJSONValue(paths)
+	if err != nil {
+		return nil, openapi3.Components{}, fmt.Errorf("cannot inline paths: %w", err)
+	}
+
+	inliner := &refInliner{
+		components: componentsJSON,
+		keep:       keep,
+		inlined:    make(map[string]struct{}),
+	}
+
+	pathsJSON = inliner.walk(pathsJSON, nil)
+
+	// the components left in the document may refer the inlined components too
+	if kinds, ok := componentsJSON.(map[string]interface{}); ok {
+		for kind, kindValue := range kinds {
+			named, ok := kindValue.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for name, component := range named {
+				named[name] = inliner.walk(component, []string{"#/components/" + kind + "/" + name})
+			}
+		}
+	}
+
+	var outPaths openapi3.Paths
+	if err = fromJSONValue(pathsJSON, &outPaths); err != nil {
+		return nil, openapi3.Components{}, fmt.Errorf("cannot inline paths: %w", err)
+	}
+
+	var outComponents openapi3.Components
+	if err = fromJSONValue(componentsJSON, &outComponents); err != nil {
+		return nil, openapi3.Components{}, fmt.Errorf("cannot inline components: %w", err)
+	}
+
+	// remove the inlined components which are not referenced anymore, i.e: not the recursive schema
+	used := reachableRefs(outPaths, nil, &outComponents)
+	for ref := range inliner.inlined {
+		if _, exist := used[ref]; !exist {
+			utils.DeleteComponent(&outComponents, ref)
+		}
+	}
+
+	return outPaths, outComponents, nil
+}
+
+// structSchemaRefs returns the schema references of the request and response body which is named Go struct.
+func (r *Registry) structSchemaRefs(paths openapi3.Paths, components *openapi3.Components) map[string]struct{} {
+	refs := make(map[string]struct{})
+	keepSchemas := func(content openapi3.Content, bodies map[string]interface{}) {
+		for contentType, body := range bodies {
+			mediaType := content.Get(contentType)
+			if !isNamedStruct(body) || mediaType == nil || mediaType.Schema == nil || mediaType.Schema.Ref == "" {
+				continue
+			}
+
+			refs[mediaType.Schema.Ref] = struct{}{}
+		}
+	}
+
+	for _, route := range r.routes {
+		pathItem := paths[route.Path]
+		if pathItem == nil {
+			continue
+		}
+
+		operation := pathItem.Operations()[route.Method]
+		if operation == nil {
+			continue
+		}
+
+		if requestBody := resolveRequestBody(components, operation.RequestBody); requestBody != nil && route.Request != nil {
+			keepSchemas(requestBody.Content, route.Request.Info().Bodies)
+		}
+
+		for httpCode, respInstance := range route.Responses {
+			if resp := resolveResponse(components, operation.Responses[httpCode]); resp != nil {
+				keepSchemas(resp.Content, respInstance.Info().Bodies)
+			}
+		}
+	}
+
+	return refs
+}
+
+func resolveRequestBody(components *openapi3.Components, requestBody *openapi3.RequestBodyRef) *openapi3.RequestBody {
+	if requestBody == nil {
+		return nil
+	}
+
+	if requestBody.Ref == "" {
+		return requestBody.Value
+	}
+
+	if component, ok := utils.ComponentByRef(components, requestBody.Ref).(*openapi3.RequestBodyRef); ok {
+		return component.Value
+	}
+
+	return nil
+}
+
+func resolveResponse(components *openapi3.Components, resp *openapi3.ResponseRef) *openapi3.Response {
+	if resp == nil {
+		return nil
+	}
+
+	if resp.Ref == "" {
+		return resp.Value
+	}
+
+	if component, ok := utils.ComponentByRef(components, resp.Ref).(*openapi3.ResponseRef); ok {
+		return component.Value
+	}
+
+	return nil
+}
+
+// isNamedStruct returns true if the value is a named struct or the pointer of it, i.e: main.Pet but not struct{}.
+func isNamedStruct(value interface{}) bool {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t != nil && t.Kind() == reflect.Struct && t.Name() != ""
+}
+
+// refInliner replaces the $ref object in the JSON value with the component value.
+type refInliner struct {
+	// components is the JSON value of openapi3.Components
+	components interface{}
+
+	// keep map k = reference which must not be inlined
+	keep map[string]struct{}
+
+	// inlined map k = reference which is inlined at least once
+	inlined map[string]struct{}
+}
+
+// inlinable returns true if the reference kind can be inlined and not kept by the policy.
+func (i *refInliner) inlinable(ref string) bool {
+	if _, exist := i.keep[ref]; exist {
+		return false
+	}
+
+	for _, kind := range []string{"schemas", "parameters", "headers", "requestBodies", "responses"} {
+		if strings.HasPrefix(ref, "#/components/"+kind+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// walk returns the value with the inlined references, stack is the references being inlined to detect the recursive one.
+func (i *refInliner) walk(v interface{}, stack []string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && len(v) == 1 {
+			if !i.inlinable(ref) || contains(stack, ref) {
+				return v
+			}
+
+			component := i.component(ref)
+			if component == nil {
+				return v
+			}
+
+			i.inlined[ref] = struct{}{}
+			return i.walk(copyJSONValue(component), append(stack, ref))
+		}
+
+		for key, child := range v {
+			v[key] = i.walk(child, stack)
+		}
+
+	case []interface{}:
+		for idx, child := range v {
+			v[idx] = i.walk(child, stack)
+		}
+	}
+
+	return v
+}
+
+// component returns the JSON value of the referenced component, nil if not exist.
+func (i *refInliner) component(ref string) interface{} {
+	parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	kinds, ok := i.components.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	named, ok := kinds[parts[0]].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return named[strings.ReplaceAll(strings.ReplaceAll(parts[1], "~1", "/"), "~0", "~")]
+}
+
+func toJSONValue(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
+
+func fromJSONValue(v interface{}, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, out)
+}
+
+// copyJSONValue returns the deep copy of the JSON value, so the same component can be inlined in many places.
+func copyJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			out[key] = copyJSONValue(child)
+		}
+
+		return out
+
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for idx, child := range v {
+			out[idx] = copyJSONValue(child)
+		}
+
+		return out
+	}
+
+	return v
+}
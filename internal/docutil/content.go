@@ -0,0 +1,52 @@
+// Package docutil contains the helpers shared by the packages which read the routes or the generated document,
+// i.e: mock, contract, export, render, diff and clientgen.
+package docutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// IsJSON returns true if the content type is JSON, i.e: application/json or application/problem+json
+func IsJSON(contentType string) bool {
+	return strings.Contains(strings.ToLower(contentType), "json")
+}
+
+// PreferredContentType returns the JSON content type if exist, otherwise the first sorted content type.
+// content map k = content type, v = any value, i.e: openapi3.Content or request.Info Bodies.
+func PreferredContentType[V any](content map[string]V) string {
+	contentTypes := SortedKeys(content)
+	for _, contentType := range contentTypes {
+		if IsJSON(contentType) {
+			return contentType
+		}
+	}
+
+	if len(contentTypes) > 0 {
+		return contentTypes[0]
+	}
+
+	return ""
+}
+
+// Encode returns the body of the content type.
+// []byte is written as-is, string is written as-is unless the content type is JSON, and nil is empty body.
+func Encode(contentType string, body interface{}) ([]byte, error) {
+	switch v := body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		if !IsJSON(contentType) {
+			return []byte(v), nil
+		}
+	}
+
+	if IsJSON(contentType) {
+		return json.Marshal(body)
+	}
+
+	return []byte(fmt.Sprint(body)), nil
+}
@@ -0,0 +1,30 @@
+package docutil
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PathParamRegex matches the path template parameter, i.e: {id} in /pets/{id}
+var PathParamRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// PathParams returns the parameter names of the path template in order, i.e: [id] for /pets/{id}
+func PathParams(path string) []string {
+	names := make([]string, 0)
+	for _, match := range PathParamRegex.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
+
+// ExpandPath replaces the parameters of the path template with the escaped values,
+// values map k = parameter name, v = the value. The parameter without value is kept as-is.
+func ExpandPath(path string, values map[string]string) string {
+	for name, value := range values {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+
+	return path
+}
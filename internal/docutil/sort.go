@@ -0,0 +1,16 @@
+package docutil
+
+import (
+	"sort"
+)
+
+// SortedKeys returns the sorted keys of the map, so the output is always in the same order.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+package openapidoc
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/hashicorp/go-multierror"
+	"strings"
+)
+
+// validateLinks ensures that every link in components refers to an existing operationId,
+// and every mapped parameter exists on that target operation.
+func validateLinks(paths openapi3.Paths, components *openapi3.Components) (err error) {
+	if components == nil || len(components.Links) <= 0 {
+		return
+	}
+
+	// operations map k = operationId, v = the operation
+	operations := make(map[string]*openapi3.Operation)
+	for path, pathItem := range paths {
+		for method, operation := range pathItem.Operations() {
+			if operation.OperationID == "" {
+				continue
+			}
+
+			if _, exist := operations[operation.OperationID]; exist {
+				err = multierror.Append(err, fmt.Errorf("duplicate operationId '%s' on %s %s", operation.OperationID, method, path))
+				continue
+			}
+
+			operations[operation.OperationID] = operation
+		}
+	}
+
+	for linkName, linkRef := range components.Links {
+		if linkRef == nil || linkRef.Value == nil {
+			continue
+		}
+
+		operation, exist := operations[linkRef.Value.OperationID]
+		if !exist {
+			err = multierror.Append(err, fmt.Errorf("link '%s' refers to unknown operationId '%s'", linkName, linkRef.Value.OperationID))
+			continue
+		}
+
+		for paramName := range linkRef.Value.Parameters {
+			if !hasParameter(components, operation, paramName) {
+				err = multierror.Append(err, fmt.Errorf(
+					"link '%s' maps parameter '%s' which does not exist on operationId '%s'",
+					linkName, paramName, linkRef.Value.OperationID,
+				))
+			}
+		}
+	}
+
+	return
+}
+
+// hasParameter check whether operation has parameter with the name.
+// Name can be qualified using the parameter location, for example: path.id
+func hasParameter(components *openapi3.Components, operation *openapi3.Operation, name string) bool {
+	in := ""
+	if s := strings.SplitN(name, ".", 2); len(s) == 2 {
+		switch s[0] {
+		case openapi3.ParameterInPath, openapi3.ParameterInQuery, openapi3.ParameterInHeader, openapi3.ParameterInCookie:
+			in, name = s[0], s[1]
+		}
+	}
+
+	for _, paramRef := range operation.Parameters {
+		param := resolveParameter(components, paramRef)
+		if param == nil || param.Name != name {
+			continue
+		}
+
+		if in == "" || param.In == in {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveParameter returns the parameter value, look up to the components if it is a reference.
+func resolveParameter(components *openapi3.Components, paramRef *openapi3.ParameterRef) *openapi3.Parameter {
+	if paramRef == nil {
+		return nil
+	}
+
+	if paramRef.Ref == "" {
+		return paramRef.Value
+	}
+
+	target, exist := components.Parameters[strings.TrimPrefix(paramRef.Ref, "#/components/parameters/")]
+	if !exist || target == nil {
+		return nil
+	}
+
+	return target.Value
+}
@@ -0,0 +1,356 @@
+// Package mock serves the documented examples of the Registry as a fake backend.
+//
+// The handler routes the request using the documented method and path template,
+// validates the request against the documented parameters and request body,
+// then responds with the documented example.
+//
+// Client can choose the status code and the named example using Prefer header, for example:
+//
+//	Prefer: code=404, example=notFound
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy/pathpattern"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Opt is the option of the mock Handler.
+type Opt func(*Handler) error
+
+// WithoutValidation disables the request validation, so every request is answered with the documented example.
+func WithoutValidation() Opt {
+	return func(h *Handler) error {
+		h.validate = false
+		return nil
+	}
+}
+
+// Handler is the http.Handler which responds with the documented examples.
+type Handler struct {
+	validate bool
+
+	// operations matches "METHOD /path" and paths matches "/path" only, to differentiate 404 and 405
+	operations *pathpattern.Node
+	paths      *pathpattern.Node
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// operation is the value of the matched path pattern
+type operation struct {
+	route     *routers.Route
+	responses map[string]*response.Response // original responses passed to Registry.Add
+}
+
+// NewHandler returns the mock Handler of all operations registered in the Registry.
+func NewHandler(reg *openapidoc.Registry, options ...Opt) (*Handler, error) {
+	h := &Handler{
+		validate:   true,
+		operations: &pathpattern.Node{},
+		paths:      &pathpattern.Node{},
+	}
+
+	for _, option := range options {
+		if option != nil {
+			err := option(h)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	doc, err := reg.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	// load the document again, so all references value is resolved and can be used by openapi3filter
+	docJSON, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = openapi3.NewLoader().LoadFromData(docJSON)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the generated document: %w", err)
+	}
+
+	for _, route := range reg.Routes() {
+		pathItem := doc.Paths[route.Path]
+		if pathItem == nil {
+			continue
+		}
+
+		op := pathItem.Operations()[route.Method]
+		if op == nil {
+			continue
+		}
+
+		err = h.operations.Add(route.Method+" "+route.Path, &operation{
+			route: &routers.Route{
+				Spec:      doc,
+				Path:      route.Path,
+				PathItem:  pathItem,
+				Method:    route.Method,
+				Operation: op,
+			},
+			responses: route.Responses,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot add route %s %s: %w", route.Method, route.Path, err)
+		}
+
+		err = h.paths.Add(route.Path, route.Path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("cannot add route %s %s: %w", route.Method, route.Path, err)
+		}
+	}
+
+	return h, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	node, paramValues := h.operations.Match(r.Method + " " + r.URL.Path)
+	if node == nil {
+		if pathNode, _ := h.paths.Match(r.URL.Path); pathNode != nil {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not documented for %s", r.Method, r.URL.Path))
+			return
+		}
+
+		writeError(w, http.StatusNotFound, fmt.Sprintf("path %s is not documented", r.URL.Path))
+		return
+	}
+
+	op, ok := node.Value.(*operation)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "invalid route")
+		return
+	}
+
+	pathParams := make(map[string]string, len(paramValues))
+	for i, value := range paramValues {
+		pathParams[node.VariableNames[i]] = value
+	}
+
+	if h.validate {
+		err := openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      op.route,
+			Options: &openapi3filter.Options{
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		})
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request", errorDetails(err)...)
+			return
+		}
+	}
+
+	prefer := parsePrefer(r.Header.Get("Prefer"))
+	status, code, err := chooseStatus(op.route.Operation.Responses, prefer["code"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respRef := op.route.Operation.Responses[status]
+	if respRef == nil || respRef.Value == nil {
+		w.WriteHeader(code)
+		return
+	}
+
+	// documented response headers, using the value passed to header.Map
+	respInstance := op.responses[strings.ToLower(status)]
+	if respInstance != nil {
+		for key, value := range respInstance.Info().Headers {
+			w.Header().Set(key, value.Value)
+		}
+	}
+
+	contentType, mediaType := chooseContentType(respRef.Value.Content, r.Header.Get("Accept"))
+	if mediaType == nil {
+		w.WriteHeader(code)
+		return
+	}
+
+	example, err := chooseExample(mediaType, prefer["example"], respInstance, contentType)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := docutil.Encode(contentType, example)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("cannot encode example: %s", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+// parsePrefer parses Prefer header, i.e: code=404, example=notFound
+func parsePrefer(prefer string) map[string]string {
+	out := make(map[string]string)
+	for _, pref := range strings.FieldsFunc(prefer, func(r rune) bool { return r == ',' || r == ';' }) {
+		kv := strings.SplitN(strings.TrimSpace(pref), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		out[strings.ToLower(strings.TrimSpace(kv[0]))] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+	}
+
+	return out
+}
+
+// chooseStatus returns the documented status key and the actual http status code.
+// If code is empty, the lowest 2xx status is used, otherwise the lowest documented status.
+// If code is not empty, it matches the exact status, then the range (4xx), then the default.
+func chooseStatus(responses openapi3.Responses, code string) (status string, httpCode int, err error) {
+	if code == "" {
+		statuses := docutil.SortedKeys(responses)
+		for _, s := range statuses {
+			if strings.HasPrefix(s, "2") {
+				return s, statusCode(s), nil
+			}
+		}
+
+		if len(statuses) > 0 {
+			return statuses[0], statusCode(statuses[0]), nil
+		}
+
+		return "", http.StatusNoContent, nil
+	}
+
+	httpCode, err = strconv.Atoi(code)
+	if err != nil || httpCode < 100 || httpCode > 599 {
+		return "", 0, fmt.Errorf("invalid preferred status code '%s'", code)
+	}
+
+	for _, s := range []string{code, fmt.Sprintf("%cXX", code[0]), fmt.Sprintf("%cxx", code[0]), "default"} {
+		if _, exist := responses[s]; exist {
+			return s, httpCode, nil
+		}
+	}
+
+	return "", 0, fmt.Errorf("preferred status code %s is not documented", code)
+}
+
+// statusCode returns http status code of the documented status, 2xx returns 200 and default returns 500.
+func statusCode(status string) int {
+	if code, err := strconv.Atoi(status); err == nil {
+		return code
+	}
+
+	if len(status) == 3 && strings.HasSuffix(strings.ToLower(status), "xx") {
+		return int(status[0]-'0') * 100
+	}
+
+	return http.StatusInternalServerError
+}
+
+// chooseContentType returns the documented content type accepted by the client.
+// If no Accept header, JSON content type is preferred.
+func chooseContentType(content openapi3.Content, accept string) (string, *openapi3.MediaType) {
+	contentTypes := docutil.SortedKeys(content)
+	sort.Slice(contentTypes, func(i, j int) bool {
+		iJSON, jJSON := docutil.IsJSON(contentTypes[i]), docutil.IsJSON(contentTypes[j])
+		if iJSON != jJSON {
+			return iJSON
+		}
+
+		return contentTypes[i] < contentTypes[j]
+	})
+
+	if len(contentTypes) <= 0 {
+		return "", nil
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return contentTypes[0], content[contentTypes[0]]
+	}
+
+	for _, acceptType := range strings.Split(accept, ",") {
+		acceptType = strings.TrimSpace(strings.SplitN(acceptType, ";", 2)[0])
+		for _, contentType := range contentTypes {
+			if acceptType == "*/*" || acceptType == contentType ||
+				strings.HasSuffix(acceptType, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(acceptType, "*")) {
+				return contentType, content[contentType]
+			}
+		}
+	}
+
+	return contentTypes[0], content[contentTypes[0]]
+}
+
+// chooseExample returns the named example if name is not empty.
+// Otherwise, it returns the first named example, the media type example, or the Go value passed to response.Response Body.
+func chooseExample(mediaType *openapi3.MediaType, name string, respInstance *response.Response, contentType string) (interface{}, error) {
+	if name != "" {
+		exampleRef, exist := mediaType.Examples[name]
+		if !exist || exampleRef == nil || exampleRef.Value == nil {
+			return nil, fmt.Errorf("preferred example '%s' is not documented", name)
+		}
+
+		return exampleRef.Value.Value, nil
+	}
+
+	for _, exampleName := range docutil.SortedKeys(mediaType.Examples) {
+		if exampleRef := mediaType.Examples[exampleName]; exampleRef != nil && exampleRef.Value != nil {
+			return exampleRef.Value.Value, nil
+		}
+	}
+
+	if mediaType.Example != nil {
+		return mediaType.Example, nil
+	}
+
+	if respInstance != nil {
+		return respInstance.Info().Bodies[contentType], nil
+	}
+
+	return nil, nil
+}
+
+// errorBody is the response body when the request cannot be answered with the documented example.
+type errorBody struct {
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, code int, message string, details ...string) {
+	b, _ := json.Marshal(errorBody{Message: message, Details: details})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
+
+// errorDetails flatten the openapi3.MultiError returned by openapi3filter.
+func errorDetails(err error) []string {
+	if multiErr, ok := err.(openapi3.MultiError); ok {
+		details := make([]string, 0, len(multiErr))
+		for _, e := range multiErr {
+			details = append(details, errorDetails(e)...)
+		}
+
+		return details
+	}
+
+	return []string{err.Error()}
+}
@@ -0,0 +1,161 @@
+package mock_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/header"
+	"github.com/yusufsyaifudin/openapidoc/mock"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type Pet struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+func newHandler(t *testing.T) *mock.Handler {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/pets",
+		request.NewRequest().Body("application/json", Pet{ID: 1, Name: "Kitty"}),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", Pet{ID: 1, Name: "Kitty"}),
+		},
+	)
+
+	reg.Add(http.MethodGet, "/pets/{id}",
+		request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+		map[string]*response.Response{
+			"200": response.NewResponse().
+				Header(header.NewHeader().Add("X-Request-Id", header.Map{Value: "abc"})).
+				Body("application/json", Pet{}, response.WithExamples(
+					response.Example{Name: "kitty", Value: Pet{ID: 1, Name: "Kitty"}},
+					response.Example{Name: "doggy", Value: Pet{ID: 2, Name: "Doggy"}},
+				)),
+			"404": response.NewResponse().Body("application/json", Error{}, response.WithExamples(
+				response.Example{Name: "notFound", Value: Error{Message: "pet not found"}},
+			)),
+			"5XX": response.NewResponse().Body("application/json", Error{Message: "internal error"}),
+		},
+	)
+
+	h, err := mock.NewHandler(reg)
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+	return h
+}
+
+func serve(h http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	h := newHandler(t)
+
+	t.Run("default status and first example", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/pets/1", "", nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "abc", rec.Header().Get("X-Request-Id"))
+		assert.JSONEq(t, `{"id":2,"name":"Doggy"}`, rec.Body.String())
+	})
+
+	t.Run("preferred example", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/pets/1", "", map[string]string{"Prefer": "example=kitty"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Kitty"}`, rec.Body.String())
+	})
+
+	t.Run("preferred status and example", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/pets/1", "", map[string]string{"Prefer": `code=404, example="notFound"`})
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"message":"pet not found"}`, rec.Body.String())
+	})
+
+	t.Run("preferred status matches range", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/pets/1", "", map[string]string{"Prefer": "code=503"})
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"message":"internal error"}`, rec.Body.String())
+	})
+
+	t.Run("undocumented preferred status", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/pets/1", "", map[string]string{"Prefer": "code=401"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("undocumented preferred example", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/pets/1", "", map[string]string{"Prefer": "example=bird"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("valid request body", func(t *testing.T) {
+		rec := serve(h, http.MethodPost, "/pets", `{"id":3,"name":"Bird"}`, nil)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Kitty"}`, rec.Body.String())
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		rec := serve(h, http.MethodPost, "/pets", `{"id":"three"}`, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body struct {
+			Message string   `json:"message"`
+			Details []string `json:"details"`
+		}
+
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "invalid request", body.Message)
+		assert.NotEmpty(t, body.Details)
+	})
+
+	t.Run("invalid path param", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/pets/abc", "", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := serve(h, http.MethodDelete, "/pets/1", "", nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rec := serve(h, http.MethodGet, "/owners", "", nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandlerWithoutValidation(t *testing.T) {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/pets",
+		request.NewRequest().Body("application/json", Pet{}),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", Pet{ID: 1, Name: "Kitty"}),
+		},
+	)
+
+	h, err := mock.NewHandler(reg, mock.WithoutValidation())
+	assert.NoError(t, err)
+
+	rec := serve(h, http.MethodPost, "/pets", `{"id":"three"}`, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
@@ -0,0 +1,166 @@
+// Package openapidoctest provides test helpers to compare the generated document with the golden file.
+//
+// Run the test with -update flag or OPENAPIDOC_UPDATE environment variable to rewrite the golden files
+// using the current output:
+//
+//	go test ./... -update
+//	OPENAPIDOC_UPDATE=1 go test ./...
+//
+// The -update flag is registered by this package only if it is not registered yet,
+// otherwise the registered -update flag is used, i.e: the flag of other golden file package imported before.
+// Because this package is initialized before the test package, the test package must not register its own -update flag,
+// use flag.Lookup instead:
+//
+//	update := flag.Lookup("update").Value.(flag.Getter).Get().(bool)
+package openapidoctest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/schema"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv is the environment variable to rewrite the golden files, i.e: OPENAPIDOC_UPDATE=1
+const UpdateEnv = "OPENAPIDOC_UPDATE"
+
+// UpdateFlag is the flag to rewrite the golden files, i.e: go test ./... -update
+const UpdateFlag = "update"
+
+func init() {
+	if flag.Lookup(UpdateFlag) == nil {
+		flag.Bool(UpdateFlag, false, "rewrite the golden files using the current output")
+	}
+}
+
+// updateGolden returns true if UpdateEnv is true, or the -update flag is set.
+// The flag is looked up when the assertion runs, because it may be registered by other package.
+func updateGolden() bool {
+	if update, err := strconv.ParseBool(os.Getenv(UpdateEnv)); err == nil && update {
+		return true
+	}
+
+	updateFlag := flag.Lookup(UpdateFlag)
+	if updateFlag == nil {
+		return false
+	}
+
+	getter, ok := updateFlag.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+
+	update, _ := getter.Get().(bool)
+	return update
+}
+
+// AssertGolden compares the normalized YAML of source with the golden file, and shows unified diff on mismatch.
+// source can be one of:
+// * *openapidoc.Registry, the output of Generate is compared
+// * *openapi3.T
+// * schema.GenerateOut, the schemas and examples are compared as components
+//
+// Returns true if the output is equal with the golden file, or when the golden file is updated.
+func AssertGolden(t testing.TB, source interface{}, golden string) bool {
+	t.Helper()
+
+	actual, err := Normalize(source)
+	if err != nil {
+		t.Errorf("openapidoctest: cannot normalize %T: %s", source, err)
+		return false
+	}
+
+	if updateGolden() {
+		err = os.MkdirAll(filepath.Dir(golden), 0o755)
+		if err == nil {
+			err = os.WriteFile(golden, actual, 0o644)
+		}
+
+		if err != nil {
+			t.Errorf("openapidoctest: cannot update golden file %s: %s", golden, err)
+			return false
+		}
+
+		t.Logf("openapidoctest: golden file %s is updated", golden)
+		return true
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Errorf("openapidoctest: cannot read golden file %s, run the test with -%s or %s=1 to create it: %s", golden, UpdateFlag, UpdateEnv, err)
+		return false
+	}
+
+	// ignore the different line ending, i.e: the file is checked out on Windows
+	expectedStr := strings.ReplaceAll(string(expected), "\r\n", "\n")
+	actualStr := string(actual)
+	if expectedStr == actualStr {
+		return true
+	}
+
+	unifiedDiff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expectedStr),
+		B:        difflib.SplitLines(actualStr),
+		FromFile: golden,
+		ToFile:   "actual",
+		Context:  3,
+	})
+
+	t.Errorf("openapidoctest: output is not equal with golden file %s, run the test with -%s or %s=1 to rewrite it:\n%s", golden, UpdateFlag, UpdateEnv, unifiedDiff)
+	return false
+}
+
+// Normalize returns YAML with sorted keys and 2 spaces indentation, the same as the generated document in the examples.
+// See AssertGolden for the supported source.
+func Normalize(source interface{}) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	switch v := source.(type) {
+	case *openapidoc.Registry:
+		var doc *openapi3.T
+		doc, err = v.Generate()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err = doc.MarshalJSON()
+
+	case *openapi3.T:
+		b, err = v.MarshalJSON()
+
+	case schema.GenerateOut:
+		components := openapi3.Components{
+			Schemas:  v.Schemas,
+			Examples: v.Examples,
+		}
+
+		b, err = components.MarshalJSON()
+
+	default:
+		return nil, fmt.Errorf("not supported type %T", source)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var i interface{}
+	err = json.Unmarshal(b, &i)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.YamlMarshalIndent(i)
+}
@@ -0,0 +1,94 @@
+package openapidoctest_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc/openapidoctest"
+	"github.com/yusufsyaifudin/openapidoc/schema"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setUpdate sets the -update flag registered by openapidoctest, and restores it when the test is done.
+func setUpdate(t *testing.T, value string) {
+	t.Helper()
+
+	updateFlag := flag.Lookup(openapidoctest.UpdateFlag)
+	if !assert.NotNil(t, updateFlag) {
+		t.FailNow()
+	}
+
+	updated := updateFlag.Value.String()
+	assert.NoError(t, flag.Set(openapidoctest.UpdateFlag, value))
+	t.Cleanup(func() { _ = flag.Set(openapidoctest.UpdateFlag, updated) })
+}
+
+// recorder records the error message instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGolden(t *testing.T) {
+	type Pet struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	gen, err := schema.NewGenerator()
+	assert.NoError(t, err)
+
+	out, err := gen.Generate(context.Background(), Pet{ID: 1, Name: "Kitty"})
+	assert.NoError(t, err)
+
+	assert.True(t, openapidoctest.AssertGolden(t, out, "testdata/schema.yaml"))
+
+	t.Run("mismatch shows unified diff", func(t *testing.T) {
+		// the mismatch is not rewritten even if the test is run with -update
+		setUpdate(t, "false")
+
+		golden := filepath.Join(t.TempDir(), "schema.yaml")
+		b, err := openapidoctest.Normalize(out)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(golden, b, 0o644))
+
+		out, err := gen.Generate(context.Background(), Pet{ID: 2, Name: "Kitty"})
+		assert.NoError(t, err)
+
+		rec := &recorder{TB: t}
+		assert.False(t, openapidoctest.AssertGolden(rec, out, golden))
+		assert.Len(t, rec.errors, 1)
+		assert.Contains(t, rec.errors[0], "-        example: 1")
+		assert.Contains(t, rec.errors[0], "+        example: 2")
+	})
+
+	t.Run("update using the flag", func(t *testing.T) {
+		golden := filepath.Join(t.TempDir(), "flag", "schema.yaml")
+		setUpdate(t, "true")
+
+		assert.True(t, openapidoctest.AssertGolden(t, out, golden))
+		assert.FileExists(t, golden)
+	})
+
+	t.Run("update using the environment variable", func(t *testing.T) {
+		golden := filepath.Join(t.TempDir(), "env", "schema.yaml")
+		t.Setenv(openapidoctest.UpdateEnv, "1")
+
+		assert.True(t, openapidoctest.AssertGolden(t, out, golden))
+		assert.FileExists(t, golden)
+	})
+
+	t.Run("not supported type", func(t *testing.T) {
+		_, err := openapidoctest.Normalize(Pet{})
+		assert.Error(t, err)
+	})
+}
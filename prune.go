@@ -0,0 +1,61 @@
+package openapidoc
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+)
+
+// PruneComponents generates only the components which are reachable from the paths and security requirements,
+// i.e: the schemas of the replaced routes or the intermediate schemas which is not referenced anymore.
+// Use UnusedComponents to list them instead.
+func PruneComponents() func(*generateOpt) {
+	return func(o *generateOpt) {
+		o.prune = true
+	}
+}
+
+// UnusedComponents returns the sorted references of the components which are not reachable
+// from the paths and security requirements, i.e: #/components/schemas/main.Pet.
+// opts is the same as Generate, so the unused components of the filtered document can be listed.
+func (r *Registry) UnusedComponents(opts ...func(*generateOpt)) ([]string, error) {
+	doc, err := r.Generate(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	used := reachableRefs(doc.Paths, doc.Security, &doc.Components)
+	unused := make([]string, 0)
+	for _, ref := range utils.ComponentRefs(&doc.Components) {
+		if _, exist := used[ref]; !exist {
+			unused = append(unused, ref)
+		}
+	}
+
+	return unused, nil
+}
+
+// reachableRefs returns the references used by the paths, including the security schemes used by the security requirements.
+func reachableRefs(paths openapi3.Paths, security openapi3.SecurityRequirements, components *openapi3.Components) map[string]struct{} {
+	values := make([]interface{}, 0, len(paths))
+	requirements := append(openapi3.SecurityRequirements{}, security...)
+	for _, pathItem := range paths {
+		values = append(values, pathItem)
+		for _, operation := range pathItem.Operations() {
+			if operation.Security != nil {
+				requirements = append(requirements, *operation.Security...)
+			}
+		}
+	}
+
+	// security requirement refers the security scheme by name instead of $ref
+	for _, requirement := range requirements {
+		for name := range requirement {
+			values = append(values, &openapi3.SecuritySchemeRef{
+				Ref: fmt.Sprintf("#/components/securitySchemes/%s", name),
+			})
+		}
+	}
+
+	return utils.CollectRefs(components, values...)
+}
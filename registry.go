@@ -10,13 +10,25 @@ import (
 	"github.com/yusufsyaifudin/openapidoc/utils"
 	"hash/fnv"
 	"net/http"
+	"sort"
 	"strings"
 )
 
 type Config struct {
-	generator  *openapi3gen.Generator
-	serverInfo *openapi3.Info
-	servers    openapi3.Servers
+	generator      *openapi3gen.Generator
+	serverInfo     *openapi3.Info
+	servers        openapi3.Servers
+	extensions     map[string]interface{}
+	duplicateRoute DuplicateRoutePolicy
+
+	componentConflict utils.ConflictPolicy
+	schemaNamer       utils.SchemaNamer
+	referencePolicy   ReferencePolicy
+	validatorTags     bool
+	strictTags        bool
+	requiredPolicy    utils.RequiredPolicy
+	constEnums        *utils.ConstEnums
+	typeSchemas       utils.TypeSchemas
 }
 
 func WithGenerator(gen *openapi3gen.Generator) func(*Config) {
@@ -37,30 +49,187 @@ func WithServers(servers openapi3.Servers) func(*Config) {
 	}
 }
 
+// WithDuplicateRoute sets what Add does when the same method and path is already added, by default DuplicateRouteError.
+func WithDuplicateRoute(policy DuplicateRoutePolicy) func(*Config) {
+	return func(config *Config) {
+		config.duplicateRoute = policy
+	}
+}
+
+// WithComponentConflict sets what Add does when the generated component has the same name
+// but different structure with the component added by other route, by default utils.ConflictFail.
+// Identical components with the same name is always shared.
+func WithComponentConflict(policy utils.ConflictPolicy) func(*Config) {
+	return func(config *Config) {
+		config.componentConflict = policy
+	}
+}
+
+// WithValidatorTags maps the go-playground/validator rules in the validate tag to the schema constraints,
+// i.e: `validate:"required,min=1,max=50,email"`, see utils.ApplyValidatorTag.
+// It is only applied by the default generator, not the generator set using WithGenerator.
+func WithValidatorTags() func(*Config) {
+	return func(config *Config) {
+		config.validatorTags = true
+	}
+}
+
+// WithStrictTags returns error when the openapi3 tag has unknown keyword, i.e: `openapi3:"descr:Pet name"`.
+// It is only applied by the default generator, not the generator set using WithGenerator.
+func WithStrictTags() func(*Config) {
+	return func(config *Config) {
+		config.strictTags = true
+	}
+}
+
+// WithRequiredPolicy sets how the required properties of the struct schema is decided, by default utils.RequiredExplicit.
+// The field with `openapi3:"required"` or `openapi3:"optional"` always overrides the policy.
+// It is only applied by the default generator, not the generator set using WithGenerator.
+func WithRequiredPolicy(policy utils.RequiredPolicy) func(*Config) {
+	return func(config *Config) {
+		config.requiredPolicy = policy
+	}
+}
+
+// WithConstEnums writes the typed constants of the named type as its enum, see utils.ConstEnums,
+// i.e: const ( KindCat PetKind = "cat"; KindDog PetKind = "dog" ) is the enum of PetKind.
+// It loads the Go source of the type package, so it must be run where the source is available, i.e: go generate.
+// It is only applied by the default generator, not the generator set using WithGenerator.
+func WithConstEnums() func(*Config) {
+	return func(config *Config) {
+		config.constEnums = utils.NewConstEnums()
+	}
+}
+
+// WithTypeSchema uses the schema for the type of value instead of the generated one,
+// i.e: WithTypeSchema(big.Int{}, openapi3.NewStringSchema()) for the type which cannot implement utils.SchemaProvider.
+// The schema replaces the generated one, so the type is still traversed by openapi3gen before it is replaced.
+// It is only applied by the default generator, not the generator set using WithGenerator.
+func WithTypeSchema(value interface{}, schema *openapi3.Schema) func(*Config) {
+	return func(config *Config) {
+		if config.typeSchemas == nil {
+			config.typeSchemas = make(utils.TypeSchemas)
+		}
+
+		config.typeSchemas.Register(value, schema)
+	}
+}
+
+// WithSchemaNamer sets how the body schema is named, by default utils.NewSchemaNamer.
+func WithSchemaNamer(namer utils.SchemaNamer) func(*Config) {
+	return func(config *Config) {
+		config.schemaNamer = namer
+	}
+}
+
+// WithExtension adds vendor extension to the root of the document, i.e: x-amazon-apigateway-policy.
+func WithExtension(key string, value interface{}) func(*Config) {
+	return func(config *Config) {
+		if config.extensions == nil {
+			config.extensions = make(map[string]interface{})
+		}
+
+		config.extensions[key] = value
+	}
+}
+
+// OperationConfig is the options of the added method and path, set using the func(*OperationConfig) options,
+// i.e: WithOperationID, WithOperationTags, WithOperationExtension and WithOperationAudience.
+type OperationConfig struct {
+	operationID string
+	tags        []string
+	audiences   []string
+	extensions  map[string]interface{}
+}
+
+func (o OperationConfig) clone() *OperationConfig {
+	cloned := &OperationConfig{
+		operationID: o.operationID,
+		tags:        append([]string{}, o.tags...),
+		audiences:   append([]string{}, o.audiences...),
+	}
+
+	if o.extensions != nil {
+		cloned.extensions = make(map[string]interface{}, len(o.extensions))
+		for k, v := range o.extensions {
+			cloned.extensions[k] = v
+		}
+	}
+
+	return cloned
+}
+
+// WithOperationID sets the operationId of the added method and path.
+// This id can be used as the target of response.Response Link.
+func WithOperationID(id string) func(*OperationConfig) {
+	return func(o *OperationConfig) {
+		o.operationID = strings.TrimSpace(id)
+	}
+}
+
+// WithOperationTags sets the tags of the added method and path, used to group the operations, i.e: pets.
+func WithOperationTags(tags ...string) func(*OperationConfig) {
+	return func(o *OperationConfig) {
+		for _, tag := range tags {
+			if tag = strings.TrimSpace(tag); tag != "" && !contains(o.tags, tag) {
+				o.tags = append(o.tags, tag)
+			}
+		}
+	}
+}
+
+// WithOperationExtension adds vendor extension to the added method and path, i.e: x-rate-limit or x-internal.
+func WithOperationExtension(key string, value interface{}) func(*OperationConfig) {
+	return func(o *OperationConfig) {
+		if o.extensions == nil {
+			o.extensions = make(map[string]interface{})
+		}
+
+		o.extensions[key] = value
+	}
+}
+
 type Registry struct {
 	Config *Config
 
 	paths      map[string]*openapi3.PathItem
 	components *openapi3.Components
-	err        error
+	routes     map[string]Route
+	merger     *utils.Merger
+
+	// errs is the errors of Add in the added order, returned by Generate.
+	// The error of the method and path is cleared when it is successfully added again or removed.
+	errs []routeError
+}
+
+// routeError is the error of Add with its route key.
+type routeError struct {
+	key string
+	err error
 }
 
 func NewRegistry(configs ...func(*Config)) *Registry {
 	config := &Config{
-		generator:  openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(customizer)),
-		serverInfo: &openapi3.Info{},
-		servers:    openapi3.Servers{},
+		serverInfo:  &openapi3.Info{},
+		servers:     openapi3.Servers{},
+		schemaNamer: utils.NewSchemaNamer(),
 	}
 
 	for _, cfg := range configs {
 		cfg(config)
 	}
 
+	if config.generator == nil {
+		config.generator = openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(config.schemaCustomizer()))
+	}
+
 	r := &Registry{
 		Config:     config,
 		paths:      make(map[string]*openapi3.PathItem),
 		components: &openapi3.Components{},
-		err:        nil,
+		routes:     make(map[string]Route),
+		merger:     utils.NewMerger(config.componentConflict),
+		errs:       make([]routeError, 0),
 	}
 	return r
 }
@@ -70,46 +239,175 @@ func NewRegistry(configs ...func(*Config)) *Registry {
 // path contains URL path such as /api/v1/xxx
 // req contains request body, header, params, etc..
 // resp contains map of http status as key and response body as value, for example: 200:&response.Response{}
+// opts contains operation options, such as WithOperationID
 //
 // Multiple path with different method can be added.
-func (r *Registry) Add(method string, path string, req *request.Request, resp map[string]*response.Response) {
-	if req == nil {
+// The error is returned by Generate, use TryAdd to get the error immediately.
+// The error is cleared when the same method and path is successfully added again, i.e: using Replace, or removed using Remove.
+// Nil req or resp is ignored, use TryAdd to get it as the error.
+func (r *Registry) Add(method string, path string, req *request.Request, resp map[string]*response.Response, opts ...func(*OperationConfig)) {
+	if req == nil || resp == nil {
 		return
 	}
 
+	if err := r.TryAdd(method, path, req, resp, opts...); err != nil {
+		r.errs = append(r.errs, routeError{key: routeKey(method, path), err: err})
+	}
+}
+
+// TryAdd is the same as Add, but returns the error immediately instead of returned by Generate.
+// The error is *AddError, or multierror of *AddError when more than one response is failed; use errors.As to get the detail.
+// If error is returned, nothing is added to the Registry.
+func (r *Registry) TryAdd(method string, path string, req *request.Request, resp map[string]*response.Response, opts ...func(*OperationConfig)) error {
+	return r.add(method, path, req, resp, r.Config.duplicateRoute, opts...)
+}
+
+// Replace replaces the added method and path, regardless of the duplicate route policy.
+// The components which are not referenced anymore is removed.
+// It returns error wrapping ErrRouteNotFound if the method and path is not added yet.
+func (r *Registry) Replace(method string, path string, req *request.Request, resp map[string]*response.Response, opts ...func(*OperationConfig)) error {
+	if _, exist := r.routes[routeKey(method, path)]; !exist {
+		return &AddError{Method: method, Path: path, Err: ErrRouteNotFound}
+	}
+
+	return r.add(method, path, req, resp, DuplicateRouteReplace, opts...)
+}
+
+func (r *Registry) add(method string, path string, req *request.Request, resp map[string]*response.Response, policy DuplicateRoutePolicy, opts ...func(*OperationConfig)) error {
+	if req == nil {
+		return &AddError{Method: method, Path: path, Err: fmt.Errorf("request must not be nil")}
+	}
+
 	if resp == nil {
-		return
+		return &AddError{Method: method, Path: path, Err: fmt.Errorf("responses must not be nil")}
+	}
+
+	operationOpts := &OperationConfig{}
+	existing, duplicate := r.routes[routeKey(method, path)]
+	if duplicate {
+		switch policy {
+		case DuplicateRouteReplace:
+		case DuplicateRouteMerge:
+			// the new responses replace the existing responses with the same status,
+			// and the new operation options replace the existing one if set.
+			merged := make(map[string]*response.Response, len(existing.Responses)+len(resp))
+			for httpCode, respInstance := range existing.Responses {
+				merged[httpCode] = respInstance
+			}
+
+			for httpCode, respInstance := range resp {
+				merged[strings.ToLower(httpCode)] = respInstance
+			}
+
+			resp = merged
+			operationOpts = existing.operation.clone()
+
+		default:
+			return &AddError{Method: method, Path: path, Err: ErrDuplicateRoute}
+		}
+	}
+
+	for _, opt := range opts {
+		opt(operationOpts)
+	}
+
+	if err := utils.ValidateExtensions(operationOpts.extensions); err != nil {
+		return &AddError{Method: method, Path: path, Err: fmt.Errorf("invalid operation extension: %w", err)}
 	}
 
 	hasher := fnv.New32()
 	_, err := hasher.Write([]byte(fmt.Sprintf("%s.%s", method, path)))
 	if err != nil {
-		err = fmt.Errorf("cannot hash method and path request: %w", err)
-		r.err = multierror.Append(r.err, err)
-		return
+		return &AddError{Method: method, Path: path, Err: fmt.Errorf("cannot hash method and path request: %w", err)}
 	}
 
 	requestName := fmt.Sprintf("%d", hasher.Sum32())
 
-	reqComp, err := req.Components(r.Config.generator, requestName)
+	// generate all components first, so every error of the request and responses is returned,
+	// and nothing is added to the Registry when one of them is failed.
+	var addErr error
+	reqComp, err := req.Components(r.Config.generator, requestName, utils.WithSchemaNamer(r.Config.schemaNamer))
 	if err != nil {
-		err = fmt.Errorf("cannot create components for the request payload: %w", err)
-		r.err = multierror.Append(r.err, err)
-		return
+		addErr = multierror.Append(addErr, newAddError(method, path, "", fmt.Errorf("cannot create components for the request payload: %w", err)))
+	}
+
+	// httpCodes is sorted, so the errors and generated document is always in the same order.
+	httpCodes := make([]string, 0, len(resp))
+	for httpCode, respInstance := range resp {
+		if respInstance != nil {
+			httpCodes = append(httpCodes, httpCode)
+		}
+	}
+
+	sort.Strings(httpCodes)
+
+	// respComps map k = http code in lower case, v = the response components
+	respComps := make(map[string]openapi3.Components, len(httpCodes))
+	for _, httpCode := range httpCodes {
+		// TODO: validate http code, must valid range of http codes or 1xx, 2xx, etc
+		respComp, err := resp[httpCode].Components(r.Config.generator, requestName, strings.ToLower(httpCode), utils.WithSchemaNamer(r.Config.schemaNamer))
+		if err != nil {
+			err = fmt.Errorf("cannot create components for the response payload: %w", err)
+			addErr = multierror.Append(addErr, newAddError(method, path, httpCode, err))
+			continue
+		}
+
+		respComps[strings.ToLower(httpCode)] = respComp
+	}
+
+	if addErr != nil {
+		if multiErr, ok := addErr.(*multierror.Error); ok && len(multiErr.Errors) == 1 {
+			return multiErr.Errors[0]
+		}
+
+		return addErr
+	}
+
+	// keep the current state, so it can be restored when the components cannot be merged
+	snapshot := r.snapshot(method, path)
+
+	// only remove the existing route after all components is successfully created
+	if duplicate {
+		r.remove(method, path)
+	}
+
+	// merge components from request and responses to current T,
+	// the renamed components must be referred using the new name.
+	reqRenames, err := r.merger.Merge(r.components, reqComp, fmt.Sprintf("%s %s request", method, path))
+	if err != nil {
+		r.restore(snapshot)
+		return newAddError(method, path, "", err)
+	}
+
+	// respRenames map k = http code in lower case, v = the renamed references of the response components
+	respRenames := make(map[string]map[string]string, len(respComps))
+	for _, httpCode := range httpCodes {
+		httpCode = strings.ToLower(httpCode)
+		origin := fmt.Sprintf("%s %s response %s", method, path, httpCode)
+		respRenames[httpCode], err = r.merger.Merge(r.components, respComps[httpCode], origin)
+		if err != nil {
+			r.restore(snapshot)
+			return newAddError(method, path, httpCode, err)
+		}
 	}
 
 	// add parameters from request.Request to this specific method:path
 	// this includes header params, path params, query params, etc
-	reqParams := make([]*openapi3.ParameterRef, 0)
+	// parameter names are sorted, so the generated document is always in the same order.
+	parameterRefNames := make([]string, 0, len(reqComp.Parameters))
 	for parameterRefName := range reqComp.Parameters {
+		parameterRefNames = append(parameterRefNames, parameterRefName)
+	}
+
+	sort.Strings(parameterRefNames)
+
+	reqParams := make([]*openapi3.ParameterRef, 0)
+	for _, parameterRefName := range parameterRefNames {
 		reqParams = append(reqParams, &openapi3.ParameterRef{
-			Ref: fmt.Sprintf("#/components/parameters/%s", parameterRefName),
+			Ref: renamedRef(reqRenames, fmt.Sprintf("#/components/parameters/%s", parameterRefName)),
 		})
 	}
 
-	// merge components from request to current T
-	utils.MergeComponents(r.components, reqComp)
-
 	if r.paths[path] == nil {
 		r.paths[path] = &openapi3.PathItem{}
 	}
@@ -120,7 +418,7 @@ func (r *Registry) Add(method string, path string, req *request.Request, resp ma
 	for reqBodyName := range reqComp.RequestBodies {
 		// The Add method can be called multiple times to add different method for the same path.
 
-		reqBodyRefName := fmt.Sprintf("#/components/requestBodies/%s", reqBodyName)
+		reqBodyRefName := renamedRef(reqRenames, fmt.Sprintf("#/components/requestBodies/%s", reqBodyName))
 		reqBodyRef := &openapi3.RequestBodyRef{
 			Ref: reqBodyRefName,
 		}
@@ -202,30 +500,16 @@ func (r *Registry) Add(method string, path string, req *request.Request, resp ma
 
 	}
 
-	// generate response for each http status code,
+	// add response for each http status code,
 	// i.e: http status 200 OK may have different schema for http status 404 Not Found
-	for httpCode, respInstance := range resp {
-		// TODO: validate http code, must valid range of http codes or 1xx, 2xx, etc
+	for _, httpCode := range httpCodes {
 		httpCode = strings.ToLower(httpCode)
-
-		if respInstance == nil {
-			continue
-		}
-
-		respComp, err := respInstance.Components(r.Config.generator, requestName, httpCode)
-		if err != nil {
-			err = fmt.Errorf("cannot create components for the response payload %s: %w", httpCode, err)
-			r.err = multierror.Append(r.err, err)
-			return
-		}
-
-		// merge components from request to current T
-		utils.MergeComponents(r.components, respComp)
+		respComp := respComps[httpCode]
 
 		// add responses schema to current components
 		// same method and path can multiple response with different content type
 		for respBodyName := range respComp.Responses {
-			respBodyRefName := fmt.Sprintf("#/components/responses/%s", respBodyName)
+			respBodyRefName := renamedRef(respRenames[httpCode], fmt.Sprintf("#/components/responses/%s", respBodyName))
 
 			switch method {
 			case http.MethodGet:
@@ -350,12 +634,123 @@ func (r *Registry) Add(method string, path string, req *request.Request, resp ma
 		}
 	}
 
+	// set the operation level values for this specific method:path
+	if operation := pathItem.Operations()[method]; operation != nil {
+		operation.OperationID = operationOpts.operationID
+		operation.Tags = operationOpts.tags
+		operation.ExtensionProps.Extensions = operationOpts.extensions
+	}
+
 	r.paths[path] = pathItem
+	r.routes[routeKey(method, path)] = newRoute(method, path, *operationOpts, req, resp)
+	r.clearErrors(method, path)
+	return nil
+}
+
+// clearErrors removes the errors of Add of the method and path, so it is not returned by Generate.
+// It returns false if the method and path has no error.
+func (r *Registry) clearErrors(method, path string) bool {
+	key := routeKey(method, path)
+	errs := make([]routeError, 0, len(r.errs))
+	for _, routeErr := range r.errs {
+		if routeErr.key != key {
+			errs = append(errs, routeErr)
+		}
+	}
+
+	cleared := len(errs) != len(r.errs)
+	r.errs = errs
+	return cleared
 }
 
-func (r *Registry) Generate() (*openapi3.T, error) {
-	if r.err != nil {
-		return nil, r.err
+// registrySnapshot is the state of the Registry before the method and path is added.
+type registrySnapshot struct {
+	method     string
+	path       string
+	components openapi3.Components
+	merger     *utils.Merger
+	pathItem   *openapi3.PathItem
+	operation  *openapi3.Operation
+	route      Route
+	routeExist bool
+}
+
+func (r *Registry) snapshot(method, path string) registrySnapshot {
+	snapshot := registrySnapshot{
+		method:     method,
+		path:       path,
+		components: utils.CloneComponents(*r.components),
+		merger:     r.merger.Clone(),
+		pathItem:   r.paths[path],
+	}
+
+	if snapshot.pathItem != nil {
+		snapshot.operation = snapshot.pathItem.Operations()[method]
+	}
+
+	snapshot.route, snapshot.routeExist = r.routes[routeKey(method, path)]
+	return snapshot
+}
+
+// restore returns the Registry to the snapshot state, only the method and path of the snapshot is restored.
+func (r *Registry) restore(snapshot registrySnapshot) {
+	*r.components = snapshot.components
+	r.merger = snapshot.merger
+
+	if snapshot.pathItem != nil {
+		snapshot.pathItem.SetOperation(snapshot.method, snapshot.operation)
+		r.paths[snapshot.path] = snapshot.pathItem
+	}
+
+	if snapshot.routeExist {
+		r.routes[routeKey(snapshot.method, snapshot.path)] = snapshot.route
+	}
+}
+
+// renamedRef returns the new reference if the component is renamed when merged.
+func renamedRef(renames map[string]string, ref string) string {
+	if renamed, exist := renames[ref]; exist {
+		return renamed
+	}
+
+	return ref
+}
+
+// MustGenerate is the same as Generate but panics on error, it is intended for the main package.
+func (r *Registry) MustGenerate() *openapi3.T {
+	t, err := r.Generate()
+	if err != nil {
+		panic(fmt.Errorf("openapidoc: %w", err))
+	}
+
+	return t
+}
+
+// Generate returns the document of all added routes.
+// opts filters the generated operations, i.e: ForAudience(AudiencePublic) or ForTag("pets"),
+// the filtered document only contains the components referenced by the generated operations.
+// Use PruneComponents to remove the unreferenced components from the unfiltered document.
+func (r *Registry) Generate(opts ...func(*generateOpt)) (*openapi3.T, error) {
+	var addErr error
+	for _, routeErr := range r.errs {
+		addErr = multierror.Append(addErr, routeErr.err)
+	}
+
+	if addErr != nil {
+		return nil, addErr
+	}
+
+	generateOpts := &generateOpt{}
+	for _, opt := range opts {
+		opt(generateOpts)
+	}
+
+	if err := validateLinks(r.paths, r.components); err != nil {
+		return nil, err
+	}
+
+	if err := utils.ValidateExtensions(r.Config.extensions); err != nil {
+		return nil, fmt.Errorf("invalid document extension: %w", err)
 	}
 
 	if r.Config.serverInfo == nil {
@@ -380,17 +775,70 @@ func (r *Registry) Generate() (*openapi3.T, error) {
 		}
 	}
 
+	paths, components := r.paths, *r.components
+	if generateOpts.filtered() {
+		paths, components = r.filter(generateOpts)
+	}
+
+	paths, components, err := r.inline(paths, components, r.Config.referencePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	if generateOpts.prune {
+		components = utils.FilterComponents(&components, reachableRefs(paths, nil, &components))
+	}
+
 	t := &openapi3.T{
-		ExtensionProps: openapi3.ExtensionProps{},
+		ExtensionProps: openapi3.ExtensionProps{Extensions: r.Config.extensions},
 		OpenAPI:        "3.0.3",
-		Components:     *r.components,
+		Components:     components,
 		Info:           r.Config.serverInfo,
-		Paths:          r.paths,
+		Paths:          paths,
 		Security:       nil,
 		Servers:        r.Config.servers,
-		Tags:           nil,
+		Tags:           documentTags(paths),
 		ExternalDocs:   nil,
 	}
 
 	return t, nil
 }
+
+// documentTags returns the unique tags of all operations sorted by name, nil if no operation has tag.
+func documentTags(paths openapi3.Paths) openapi3.Tags {
+	unique := make(map[string]struct{})
+	for _, pathItem := range paths {
+		for _, operation := range pathItem.Operations() {
+			for _, tag := range operation.Tags {
+				unique[tag] = struct{}{}
+			}
+		}
+	}
+
+	if len(unique) <= 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	tags := make(openapi3.Tags, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, &openapi3.Tag{Name: name})
+	}
+
+	return tags
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,2 +1,1490 @@
 package openapidoc_test
 
+import (
+	"context"
+	"errors"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/header"
+	"github.com/yusufsyaifudin/openapidoc/openapidoctest"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"github.com/yusufsyaifudin/openapidoc/schema/testasset"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"math/big"
+	"net/http"
+	"testing"
+)
+
+type Pet struct {
+	ID   int    `json:"id" openapi3:"ex:2"`
+	Name string `json:"name"`
+}
+
+func TestRegistryLink(t *testing.T) {
+	newRegistry := func(linkParams map[string]string, target string) *openapidoc.Registry {
+		reg := openapidoc.NewRegistry()
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", Pet{}),
+			map[string]*response.Response{
+				"201": response.NewResponse().
+					Body("application/json", Pet{}).
+					Link("GetPetByID", target, linkParams),
+			},
+			openapidoc.WithOperationID("createPet"),
+		)
+
+		reg.Add(http.MethodGet, "/pets/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", Pet{}),
+			},
+			openapidoc.WithOperationID("getPet"),
+		)
+
+		return reg
+	}
+
+	t.Run("valid link", func(t *testing.T) {
+		doc, err := newRegistry(map[string]string{"id": "$response.body#/id"}, "getPet").Generate()
+		assert.NoError(t, err)
+		assert.NotNil(t, doc)
+		assert.Len(t, doc.Components.Links, 1)
+		assert.Equal(t, "createPet", doc.Paths["/pets"].Post.OperationID)
+
+		respLinks := doc.Components.Responses
+		for _, resp := range respLinks {
+			if link, exist := resp.Value.Links["GetPetByID"]; exist {
+				assert.Contains(t, link.Ref, "#/components/links/")
+			}
+		}
+	})
+
+	t.Run("qualified parameter name", func(t *testing.T) {
+		_, err := newRegistry(map[string]string{"path.id": "$response.body#/id"}, "getPet").Generate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown operationId", func(t *testing.T) {
+		_, err := newRegistry(map[string]string{"id": "$response.body#/id"}, "getPets").Generate()
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown parameter", func(t *testing.T) {
+		_, err := newRegistry(map[string]string{"petId": "$response.body#/id"}, "getPet").Generate()
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistryExamples(t *testing.T) {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/pets",
+		request.NewRequest().Body("application/json", Pet{}, request.WithExamples(
+			request.Example{Name: "minimal", Summary: "Only required fields", Value: Pet{Name: "Kitty"}},
+			request.Example{Name: "full", Summary: "All fields", Value: Pet{ID: 1, Name: "Kitty"}},
+		)),
+		map[string]*response.Response{
+			"422": response.NewResponse().Body("application/json", Pet{}, response.WithExamples(
+				response.Example{Name: "validation error", Description: "Name is empty", Value: Pet{ID: 1}},
+			)),
+		},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+	assert.Len(t, doc.Components.Examples, 3)
+
+	reqBody := doc.Components.RequestBodies
+	assert.Len(t, reqBody, 1)
+	for _, body := range reqBody {
+		examples := body.Value.Content["application/json"].Examples
+		assert.Len(t, examples, 2)
+
+		exampleName := examples["minimal"].Ref[len("#/components/examples/"):]
+		assert.Equal(t, "Only required fields", doc.Components.Examples[exampleName].Value.Summary)
+	}
+
+	resp := doc.Paths["/pets"].Post.Responses["422"]
+	assert.NotNil(t, resp)
+	assert.Contains(t, resp.Ref, "#/components/responses/")
+}
+
+func TestRegistryExtensions(t *testing.T) {
+	type Item struct {
+		ID    int    `json:"id" openapi3:"x-order:1"`
+		Name  string `json:"name" openapi3:"x-order:2,x-internal"`
+		Label string `json:"label" openapi3:"x-label:primary"`
+	}
+
+	reg := openapidoc.NewRegistry(openapidoc.WithExtension("x-logo", "logo.png"))
+	reg.Add(http.MethodGet, "/items/{id}",
+		request.NewRequest().
+			PathParams(request.PathParam{Name: "id", Value: 1, Extensions: map[string]interface{}{"x-example-source": "db"}}).
+			Header(header.NewHeader().Add("Signature", header.Map{Value: "H256", Extensions: map[string]interface{}{"x-sensitive": true}})),
+		map[string]*response.Response{
+			"200": response.NewResponse().
+				Body("application/json", Item{}).
+				Extension("x-cache-ttl", 60),
+		},
+		openapidoc.WithOperationExtension("x-rate-limit", 100),
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, "logo.png", doc.Extensions["x-logo"])
+	assert.Equal(t, 100, doc.Paths["/items/{id}"].Get.Extensions["x-rate-limit"])
+	assert.Equal(t, true, doc.Components.Headers["Signature"].Value.Extensions["x-sensitive"])
+	assert.Equal(t, true, doc.Components.Parameters["headerParam.Signature"].Value.Extensions["x-sensitive"])
+
+	for _, resp := range doc.Components.Responses {
+		assert.Equal(t, 60, resp.Value.Extensions["x-cache-ttl"])
+	}
+
+	itemSchema := doc.Components.Schemas["openapidoc_test.Item"].Value
+	assert.Equal(t, float64(1), itemSchema.Properties["id"].Value.Extensions["x-order"])
+	assert.Equal(t, true, itemSchema.Properties["name"].Value.Extensions["x-internal"])
+	assert.Equal(t, "primary", itemSchema.Properties["label"].Value.Extensions["x-label"])
+
+	t.Run("invalid key", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		reg.Add(http.MethodGet, "/items",
+			request.NewRequest(),
+			map[string]*response.Response{"200": response.NewResponse()},
+			openapidoc.WithOperationExtension("rate-limit", 100),
+		)
+
+		_, err := reg.Generate()
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistryQueryParams(t *testing.T) {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodGet, "/pets",
+		request.NewRequest().QueryParams(
+			request.QueryParam{Name: "kind", Value: "cat", Description: "Pet kind"},
+			request.QueryParam{Name: "limit", Value: 10, Required: true, Extensions: map[string]interface{}{"x-max": 100}},
+			request.QueryParam{Name: "sold", Value: false},
+		),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", []Pet{}),
+		},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+
+	// map k = parameter name, v = the parameter referred by the operation
+	params := make(map[string]*openapi3.Parameter)
+	for _, paramRef := range doc.Paths["/pets"].Get.Parameters {
+		param := doc.Components.Parameters[paramRef.Ref[len("#/components/parameters/"):]]
+		if assert.NotNil(t, param) {
+			params[param.Value.Name] = param.Value
+		}
+	}
+
+	assert.Len(t, params, 3)
+	for _, param := range params {
+		assert.Equal(t, "query", param.In)
+	}
+
+	assert.Equal(t, "string", params["kind"].Schema.Value.Type)
+	assert.Equal(t, "cat", params["kind"].Example)
+	assert.Equal(t, "Pet kind", params["kind"].Description)
+	assert.False(t, params["kind"].Required)
+
+	assert.Equal(t, "integer", params["limit"].Schema.Value.Type)
+	assert.Equal(t, 10, params["limit"].Example)
+	assert.True(t, params["limit"].Required)
+	assert.Equal(t, 100, params["limit"].Extensions["x-max"])
+
+	assert.Equal(t, "boolean", params["sold"].Schema.Value.Type)
+
+	t.Run("invalid extension", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		reg.Add(http.MethodGet, "/pets",
+			request.NewRequest().QueryParams(request.QueryParam{Name: "kind", Value: "cat", Extensions: map[string]interface{}{"max": 1}}),
+			map[string]*response.Response{"200": response.NewResponse()},
+		)
+
+		_, err := reg.Generate()
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistryAddError(t *testing.T) {
+	type Address struct {
+		Zip int `json:"zip" openapi3:"ex:abc"`
+	}
+
+	type Owner struct {
+		Address Address `json:"address"`
+	}
+
+	type BadPet struct {
+		Owner Owner `json:"owner"`
+	}
+
+	t.Run("every response error is returned", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		err := reg.TryAdd(http.MethodGet, "/pets/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", BadPet{}),
+				"404": response.NewResponse().Body("application/json", BadPet{}),
+			},
+		)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "GET /pets/{id} response 200")
+		assert.Contains(t, err.Error(), "GET /pets/{id} response 404")
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, http.MethodGet, addErr.Method)
+		assert.Equal(t, "/pets/{id}", addErr.Path)
+		assert.Equal(t, "200", addErr.Status)
+		assert.Equal(t, "application/json", addErr.ContentType)
+		assert.Equal(t, "openapidoc_test.BadPet", addErr.GoType)
+		assert.Equal(t, "Owner.Address.Zip", addErr.FieldPath)
+
+		// nothing is added when TryAdd is failed
+		assert.Empty(t, reg.Routes())
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Empty(t, doc.Paths)
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", BadPet{}),
+			map[string]*response.Response{"201": response.NewResponse()},
+		)
+
+		_, err := reg.Generate()
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, "", addErr.Status)
+		assert.Equal(t, "Owner.Address.Zip", addErr.FieldPath)
+		assert.Panics(t, func() { reg.MustGenerate() })
+	})
+
+	t.Run("nil request", func(t *testing.T) {
+		err := openapidoc.NewRegistry().TryAdd(http.MethodGet, "/pets", nil, map[string]*response.Response{})
+		assert.Error(t, err)
+
+		err = openapidoc.NewRegistry().TryAdd(http.MethodGet, "/pets", request.NewRequest(), nil)
+		assert.Error(t, err)
+
+		// Add ignores it
+		reg := openapidoc.NewRegistry()
+		reg.Add(http.MethodGet, "/pets", nil, map[string]*response.Response{})
+		reg.Add(http.MethodGet, "/owners", request.NewRequest(), nil)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Empty(t, doc.Paths)
+	})
+}
+
+func TestRegistryGolden(t *testing.T) {
+	type PetCreateReq struct {
+		Pet *Pet `json:"pet" openapi3:"required:'id;name'"`
+	}
+
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/pets/{id}",
+		request.NewRequest().
+			Required(true).
+			Body("application/json", PetCreateReq{}).
+			PathParams(request.PathParam{Name: "id", Value: 1, Description: "Pet ID"}).
+			Header(header.NewHeader().Add("Signature", header.Map{Value: "H256", Required: true})),
+		map[string]*response.Response{
+			"201": response.NewResponse().
+				Description("Created").
+				Body("application/json", Pet{}).
+				Header(header.NewHeader().Add("Location", header.Map{Value: "/pets/:id", Description: "Newly created pets"})),
+		},
+		openapidoc.WithOperationID("createPet"),
+	)
+
+	openapidoctest.AssertGolden(t, reg, "testdata/openapi.yaml")
+}
+
+func TestRegistryRoutes(t *testing.T) {
+	type Owner struct {
+		Name string `json:"name"`
+	}
+
+	newRegistry := func(configs ...func(*openapidoc.Config)) *openapidoc.Registry {
+		reg := openapidoc.NewRegistry(configs...)
+		operation := []func(*openapidoc.OperationConfig){
+			openapidoc.WithOperationID("createPet"),
+			openapidoc.WithOperationTags("pets"),
+		}
+
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", Pet{}),
+			map[string]*response.Response{
+				"201": response.NewResponse().Body("application/json", Pet{}),
+				"4XX": response.NewResponse().Body("application/json", map[string]string{}),
+			},
+			operation...,
+		)
+
+		reg.Add(http.MethodGet, "/owners/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", Owner{}),
+			},
+			openapidoc.WithOperationID("getOwner"),
+		)
+
+		return reg
+	}
+
+	t.Run("lookup", func(t *testing.T) {
+		reg := newRegistry()
+		route, exist := reg.Lookup(http.MethodPost, "/pets")
+		assert.True(t, exist)
+		assert.Equal(t, "createPet", route.OperationID)
+		assert.Equal(t, []string{"201", "4xx"}, route.Statuses)
+		assert.Equal(t, map[string]string{"application/json": "openapidoc_test.Pet"}, route.RequestTypes)
+		assert.Equal(t, "openapidoc_test.Pet", route.ResponseTypes["201"]["application/json"])
+
+		_, exist = reg.Lookup(http.MethodGet, "/pets")
+		assert.False(t, exist)
+	})
+
+	t.Run("remove and garbage collect components", func(t *testing.T) {
+		reg := newRegistry()
+		assert.True(t, reg.Remove(http.MethodGet, "/owners/{id}"))
+		assert.False(t, reg.Remove(http.MethodGet, "/owners/{id}"))
+		assert.Len(t, reg.Routes(), 1)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.NotContains(t, doc.Paths, "/owners/{id}")
+		assert.NotContains(t, doc.Components.Schemas, "openapidoc_test.Owner")
+		assert.Contains(t, doc.Components.Schemas, "openapidoc_test.Pet")
+		for name := range doc.Components.Parameters {
+			assert.NotContains(t, name, "pathParam")
+		}
+	})
+
+	t.Run("duplicate route error by default", func(t *testing.T) {
+		reg := newRegistry()
+		err := reg.TryAdd(http.MethodPost, "/pets", request.NewRequest(), map[string]*response.Response{})
+		assert.True(t, errors.Is(err, openapidoc.ErrDuplicateRoute))
+	})
+
+	t.Run("error of add is cleared by remove and replace", func(t *testing.T) {
+		type BadOwner struct {
+			Name string `json:"name" openapi3:"maxLength:abc"`
+		}
+
+		reg := newRegistry()
+		reg.Add(http.MethodPost, "/pets", request.NewRequest(), map[string]*response.Response{})
+		reg.Add(http.MethodGet, "/owners", request.NewRequest().Body("application/json", BadOwner{}), map[string]*response.Response{})
+
+		_, err := reg.Generate()
+		if assert.Error(t, err) {
+			assert.True(t, errors.Is(err, openapidoc.ErrDuplicateRoute))
+		}
+
+		// the failed duplicate is replaced, the existing route is kept
+		err = reg.Replace(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", Pet{}),
+			map[string]*response.Response{"201": response.NewResponse().Body("application/json", Pet{})},
+		)
+		assert.NoError(t, err)
+
+		_, err = reg.Generate()
+		if assert.Error(t, err) {
+			assert.False(t, errors.Is(err, openapidoc.ErrDuplicateRoute))
+			assert.Contains(t, err.Error(), "GET /owners")
+		}
+
+		// the failed route is never added, but its error is removed
+		assert.True(t, reg.Remove(http.MethodGet, "/owners"))
+		assert.False(t, reg.Remove(http.MethodGet, "/owners"))
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Len(t, doc.Paths, 2)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		reg := newRegistry()
+		err := reg.Replace(http.MethodGet, "/owners/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", Pet{}),
+			},
+		)
+		assert.NoError(t, err)
+
+		route, _ := reg.Lookup(http.MethodGet, "/owners/{id}")
+		assert.Equal(t, "", route.OperationID)
+		assert.Equal(t, "openapidoc_test.Pet", route.ResponseTypes["200"]["application/json"])
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.NotContains(t, doc.Components.Schemas, "openapidoc_test.Owner")
+
+		err = reg.Replace(http.MethodDelete, "/owners/{id}", request.NewRequest(), map[string]*response.Response{})
+		assert.True(t, errors.Is(err, openapidoc.ErrRouteNotFound))
+	})
+
+	t.Run("merge policy", func(t *testing.T) {
+		reg := newRegistry(openapidoc.WithDuplicateRoute(openapidoc.DuplicateRouteMerge))
+		err := reg.TryAdd(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", Pet{}),
+			map[string]*response.Response{
+				"201": response.NewResponse().Body("application/json", Owner{}),
+				"500": response.NewResponse().Body("application/json", map[string]string{}),
+			},
+		)
+		assert.NoError(t, err)
+
+		route, _ := reg.Lookup(http.MethodPost, "/pets")
+		assert.Equal(t, "createPet", route.OperationID)
+		assert.Equal(t, []string{"pets"}, route.Tags)
+		assert.Equal(t, []string{"201", "4xx", "500"}, route.Statuses)
+		assert.Equal(t, "openapidoc_test.Owner", route.ResponseTypes["201"]["application/json"])
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Len(t, doc.Paths["/pets"].Post.Responses, 3)
+	})
+
+	t.Run("replace policy", func(t *testing.T) {
+		reg := newRegistry(openapidoc.WithDuplicateRoute(openapidoc.DuplicateRouteReplace))
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", Pet{}),
+			map[string]*response.Response{"202": response.NewResponse().Body("application/json", Pet{})},
+		)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Len(t, doc.Paths["/pets"].Post.Responses, 1)
+		assert.Contains(t, doc.Paths["/pets"].Post.Responses, "202")
+	})
+}
+
+func TestRegistryGenerateFilter(t *testing.T) {
+	type Admin struct {
+		Role string `json:"role"`
+	}
+
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodGet, "/pets/{id}",
+		request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Pet{}),
+		},
+		openapidoc.WithOperationTags("pets"),
+		openapidoc.WithOperationAudience(openapidoc.AudiencePublic, openapidoc.AudienceInternal),
+	)
+
+	reg.Add(http.MethodPost, "/admins",
+		request.NewRequest().
+			Header(header.NewHeader().Add("X-Admin-Token", header.Map{Value: "secret"})).
+			Body("application/json", Admin{}),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", Admin{}),
+		},
+		openapidoc.WithOperationTags("admin"),
+		openapidoc.WithOperationAudience(openapidoc.AudienceInternal),
+	)
+
+	reg.Add(http.MethodGet, "/health",
+		request.NewRequest(),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("text/plain", "OK"),
+		},
+	)
+
+	t.Run("public", func(t *testing.T) {
+		doc, err := reg.Generate(openapidoc.ForAudience(openapidoc.AudiencePublic))
+		assert.NoError(t, err)
+		assert.Len(t, doc.Paths, 1)
+		assert.Contains(t, doc.Paths, "/pets/{id}")
+		assert.Contains(t, doc.Components.Schemas, "openapidoc_test.Pet")
+		assert.NotContains(t, doc.Components.Schemas, "openapidoc_test.Admin")
+		assert.NotContains(t, doc.Components.Headers, "X-Admin-Token")
+		assert.Len(t, doc.Tags, 1)
+
+		// every reference in the filtered document can be resolved
+		assert.NoError(t, openapi3.NewLoader().ResolveRefsIn(doc, nil))
+	})
+
+	t.Run("internal", func(t *testing.T) {
+		doc, err := reg.Generate(openapidoc.ForAudience(openapidoc.AudienceInternal))
+		assert.NoError(t, err)
+		assert.Len(t, doc.Paths, 2)
+		assert.Contains(t, doc.Components.Schemas, "openapidoc_test.Admin")
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		doc, err := reg.Generate(openapidoc.ForTag("admin"))
+		assert.NoError(t, err)
+		assert.Len(t, doc.Paths, 1)
+		assert.Contains(t, doc.Paths, "/admins")
+	})
+
+	t.Run("audience and tag", func(t *testing.T) {
+		doc, err := reg.Generate(openapidoc.ForAudience(openapidoc.AudiencePublic), openapidoc.ForTag("admin"))
+		assert.NoError(t, err)
+		assert.Empty(t, doc.Paths)
+	})
+
+	t.Run("without filter", func(t *testing.T) {
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Len(t, doc.Paths, 3)
+	})
+
+	t.Run("link to filtered operation", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", Pet{}),
+			map[string]*response.Response{
+				"201": response.NewResponse().
+					Body("application/json", Pet{}).
+					Link("GetPet", "getPet", map[string]string{"id": "$response.body#/id"}).
+					Link("AuditPet", "auditPet", map[string]string{"id": "$response.body#/id"}),
+			},
+			openapidoc.WithOperationAudience(openapidoc.AudiencePublic),
+		)
+
+		reg.Add(http.MethodGet, "/pets/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{"200": response.NewResponse().Body("application/json", Pet{})},
+			openapidoc.WithOperationID("getPet"),
+			openapidoc.WithOperationAudience(openapidoc.AudiencePublic),
+		)
+
+		reg.Add(http.MethodGet, "/audits/pets/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{"200": response.NewResponse().Body("application/json", Pet{})},
+			openapidoc.WithOperationID("auditPet"),
+			openapidoc.WithOperationAudience(openapidoc.AudienceInternal),
+		)
+
+		linkOperationIDs := func(doc *openapi3.T) []string {
+			operationIDs := make([]string, 0)
+			for _, link := range doc.Components.Links {
+				operationIDs = append(operationIDs, link.Value.OperationID)
+			}
+
+			for _, resp := range doc.Components.Responses {
+				for _, link := range resp.Value.Links {
+					assert.NotNil(t, doc.Components.Links[link.Ref[len("#/components/links/"):]])
+				}
+			}
+
+			return operationIDs
+		}
+
+		doc, err := reg.Generate(openapidoc.ForAudience(openapidoc.AudiencePublic))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"getPet"}, linkOperationIDs(doc))
+		assert.NoError(t, openapi3.NewLoader().ResolveRefsIn(doc, nil))
+
+		// the registry is not changed by the filter
+		doc, err = reg.Generate()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"getPet", "auditPet"}, linkOperationIDs(doc))
+	})
+}
+
+func TestRegistryComponentConflict(t *testing.T) {
+	type Owner struct {
+		Name string `json:"name"`
+	}
+
+	addPet := func(reg *openapidoc.Registry) {
+		reg.Add(http.MethodGet, "/pets/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", Pet{}, response.WithSchemaName("Animal")),
+			},
+		)
+	}
+
+	addOwner := func(reg *openapidoc.Registry, data interface{}) error {
+		return reg.TryAdd(http.MethodGet, "/owners/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", data, response.WithSchemaName("Animal")),
+			},
+		)
+	}
+
+	t.Run("identical component is shared", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		addPet(reg)
+		assert.NoError(t, addOwner(reg, Pet{}))
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Contains(t, doc.Components.Schemas, "Animal")
+		assert.NotContains(t, doc.Components.Schemas, "Animal_2")
+	})
+
+	t.Run("conflict error by default", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		addPet(reg)
+		err := addOwner(reg, Owner{})
+
+		var conflictErr *utils.ConflictError
+		assert.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, "#/components/schemas/Animal", conflictErr.Ref)
+		assert.Equal(t, "GET /owners/{id} response 200", conflictErr.Origin)
+		assert.Equal(t, "GET /pets/{id} response 200", conflictErr.ExistingOrigin)
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, "200", addErr.Status)
+
+		// the failed route is not added
+		_, exist := reg.Lookup(http.MethodGet, "/owners/{id}")
+		assert.False(t, exist)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.NotContains(t, doc.Paths, "/owners/{id}")
+		assert.Contains(t, doc.Components.Schemas["Animal"].Value.Properties, "id")
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		reg := openapidoc.NewRegistry(
+			openapidoc.WithServerInfo(&openapi3.Info{Title: "Pet", Version: "v1.0.0"}),
+			openapidoc.WithComponentConflict(utils.ConflictRename),
+		)
+		addPet(reg)
+		assert.NoError(t, addOwner(reg, Owner{}))
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Contains(t, doc.Components.Schemas["Animal"].Value.Properties, "id")
+		assert.Contains(t, doc.Components.Schemas["Animal_2"].Value.Properties, "name")
+
+		respRef := doc.Paths["/owners/{id}"].Get.Responses["200"].Ref
+		resp := utils.ComponentByRef(&doc.Components, respRef).(*openapi3.ResponseRef)
+		assert.Equal(t, "#/components/schemas/Animal_2", resp.Value.Content["application/json"].Schema.Ref)
+
+		// the renamed components keep the Go value of the extension
+		err = reg.TryAdd(http.MethodGet, "/breeders/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().
+					Body("application/json", Owner{}, response.WithSchemaName("Animal")).
+					Extension("x-cache-ttl", 60),
+			},
+		)
+		assert.NoError(t, err)
+
+		doc, err = reg.Generate()
+		assert.NoError(t, err)
+		respRef = doc.Paths["/breeders/{id}"].Get.Responses["200"].Ref
+		resp = utils.ComponentByRef(&doc.Components, respRef).(*openapi3.ResponseRef)
+		assert.Equal(t, "#/components/schemas/Animal_3", resp.Value.Content["application/json"].Schema.Ref)
+		assert.Equal(t, 60, resp.Value.Extensions["x-cache-ttl"])
+
+		// every reference must be resolvable
+		b, err := doc.MarshalJSON()
+		assert.NoError(t, err)
+		loaded, err := openapi3.NewLoader().LoadFromData(b)
+		assert.NoError(t, err)
+		assert.NoError(t, loaded.Validate(context.Background()))
+	})
+
+	t.Run("replace with changed structure", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		addPet(reg)
+		err := reg.Replace(http.MethodGet, "/pets/{id}",
+			request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 1}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", Owner{}, response.WithSchemaName("Animal")),
+			},
+		)
+		assert.NoError(t, err)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Contains(t, doc.Components.Schemas["Animal"].Value.Properties, "name")
+	})
+}
+
+type Page[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+}
+
+func TestRegistrySchemaNamer(t *testing.T) {
+	addPage := func(reg *openapidoc.Registry) {
+		reg.Add(http.MethodGet, "/pets",
+			request.NewRequest(),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", &Page[Pet]{}),
+			},
+		)
+	}
+
+	t.Run("generic type", func(t *testing.T) {
+		reg := openapidoc.NewRegistry()
+		addPage(reg)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Contains(t, doc.Components.Schemas, "openapidoc_test.PageOfPet")
+	})
+
+	t.Run("custom namer", func(t *testing.T) {
+		reg := openapidoc.NewRegistry(openapidoc.WithSchemaNamer(utils.SchemaNamerFunc(utils.FullSchemaName)))
+		addPage(reg)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Contains(t, doc.Components.Schemas, "github.com_yusufsyaifudin_openapidoc_test.PageOfPet")
+	})
+}
+
+func TestRegistryPruneComponents(t *testing.T) {
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodGet, "/pets",
+		request.NewRequest().Header(header.NewHeader().Add("Signature", header.Map{Value: "H256"})),
+		map[string]*response.Response{
+			"200": response.NewResponse().
+				Body("application/json", Pet{}).
+				Header(header.NewHeader().Add("X-Rate-Limit", header.Map{Value: "100"})),
+		},
+	)
+
+	// header.Header creates both parameter and header components, only one of them is used
+	unused, err := reg.UnusedComponents()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"#/components/headers/Signature",
+		"#/components/parameters/headerParam.X-Rate-Limit",
+	}, unused)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+	assert.Contains(t, doc.Components.Headers, "Signature")
+
+	doc, err = reg.Generate(openapidoc.PruneComponents())
+	assert.NoError(t, err)
+	assert.NotContains(t, doc.Components.Headers, "Signature")
+	assert.Contains(t, doc.Components.Headers, "X-Rate-Limit")
+	assert.Contains(t, doc.Components.Parameters, "headerParam.Signature")
+	assert.NotContains(t, doc.Components.Parameters, "headerParam.X-Rate-Limit")
+	assert.Contains(t, doc.Components.Schemas, "openapidoc_test.Pet")
+
+	unused, err = reg.UnusedComponents(openapidoc.PruneComponents())
+	assert.NoError(t, err)
+	assert.Empty(t, unused)
+}
+
+func TestRegistryReferencePolicy(t *testing.T) {
+	newRegistry := func(policy openapidoc.ReferencePolicy) *openapidoc.Registry {
+		reg := openapidoc.NewRegistry(
+			openapidoc.WithServerInfo(&openapi3.Info{Title: "Pet", Version: "v1.0.0"}),
+			openapidoc.WithReferencePolicy(policy),
+		)
+
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().
+				Header(header.NewHeader().Add("Signature", header.Map{Value: "H256"})).
+				Body("application/json", Pet{}),
+			map[string]*response.Response{
+				"200": response.NewResponse().Body("application/json", map[string]string{"status": "ok"}),
+			},
+		)
+
+		return reg
+	}
+
+	assertValid := func(t *testing.T, doc *openapi3.T) {
+		b, err := doc.MarshalJSON()
+		assert.NoError(t, err)
+		loaded, err := openapi3.NewLoader().LoadFromData(b)
+		if assert.NoError(t, err) {
+			assert.NoError(t, loaded.Validate(context.Background()))
+		}
+	}
+
+	t.Run("reference all by default", func(t *testing.T) {
+		doc, err := newRegistry(openapidoc.ReferenceAll).Generate()
+		assert.NoError(t, err)
+		assert.NotEmpty(t, doc.Paths["/pets"].Post.RequestBody.Ref)
+		assert.NotEmpty(t, doc.Components.RequestBodies)
+	})
+
+	t.Run("inline all", func(t *testing.T) {
+		doc, err := newRegistry(openapidoc.InlineAll).Generate()
+		assert.NoError(t, err)
+
+		operation := doc.Paths["/pets"].Post
+		assert.Empty(t, operation.RequestBody.Ref)
+		assert.Empty(t, operation.RequestBody.Value.Content["application/json"].Schema.Ref)
+		assert.Contains(t, operation.RequestBody.Value.Content["application/json"].Schema.Value.Properties, "id")
+		assert.Empty(t, operation.Parameters[0].Ref)
+		assert.Equal(t, "Signature", operation.Parameters[0].Value.Name)
+		assert.Empty(t, operation.Responses["200"].Ref)
+		assert.Empty(t, doc.Components.RequestBodies)
+		assert.Empty(t, doc.Components.Responses)
+		assert.Empty(t, doc.Components.Schemas)
+		assertValid(t, doc)
+	})
+
+	t.Run("reference named structs", func(t *testing.T) {
+		doc, err := newRegistry(openapidoc.ReferenceNamedStructs).Generate()
+		assert.NoError(t, err)
+
+		operation := doc.Paths["/pets"].Post
+		assert.Empty(t, operation.RequestBody.Ref)
+		assert.Equal(t, "#/components/schemas/openapidoc_test.Pet", operation.RequestBody.Value.Content["application/json"].Schema.Ref)
+		assert.Empty(t, operation.Responses["200"].Value.Content["application/json"].Schema.Ref)
+		assert.Equal(t, []string{"openapidoc_test.Pet"}, keys(doc.Components.Schemas))
+		assert.Empty(t, doc.Components.RequestBodies)
+		assertValid(t, doc)
+	})
+}
+
+func keys(schemas openapi3.Schemas) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func TestRegistryValidatorTags(t *testing.T) {
+	type CreatePet struct {
+		Name    string   `json:"name" validate:"required,min=1,max=50"`
+		Email   string   `json:"email" validate:"omitempty,email"`
+		Kind    string   `json:"kind" validate:"required,oneof=cat dog"`
+		Age     int      `json:"age" validate:"gte=0,lt=100"`
+		Tags    []string `json:"tags" validate:"max=5,dive,uuid4"`
+		Website string   `json:"website" validate:"url,startswith=https"`
+		Born    string   `json:"born" validate:"datetime=2006-01-02"`
+		Adopted string   `json:"adopted" validate:"datetime=2006-01-02T15:04:05Z07:00"`
+		Fed     string   `json:"fed" validate:"datetime=15:04"`
+	}
+
+	newRegistry := func(configs ...func(*openapidoc.Config)) *openapidoc.Registry {
+		reg := openapidoc.NewRegistry(configs...)
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", CreatePet{}),
+			map[string]*response.Response{
+				"201": response.NewResponse().Body("application/json", Pet{}),
+			},
+		)
+
+		return reg
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		doc, err := newRegistry().Generate()
+		assert.NoError(t, err)
+
+		schema := doc.Components.Schemas["openapidoc_test.CreatePet"].Value
+		assert.Empty(t, schema.Required)
+		assert.Empty(t, schema.Properties["kind"].Value.Enum)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		doc, err := newRegistry(openapidoc.WithValidatorTags()).Generate()
+		assert.NoError(t, err)
+
+		schema := doc.Components.Schemas["openapidoc_test.CreatePet"].Value
+		assert.ElementsMatch(t, []string{"name", "kind"}, schema.Required)
+
+		name := schema.Properties["name"].Value
+		assert.Equal(t, uint64(1), name.MinLength)
+		assert.Equal(t, uint64(50), *name.MaxLength)
+
+		assert.Equal(t, "email", schema.Properties["email"].Value.Format)
+		assert.Equal(t, []interface{}{"cat", "dog"}, schema.Properties["kind"].Value.Enum)
+
+		age := schema.Properties["age"].Value
+		assert.Equal(t, float64(0), *age.Min)
+		assert.Equal(t, float64(100), *age.Max)
+		assert.True(t, age.ExclusiveMax)
+
+		tags := schema.Properties["tags"].Value
+		assert.Equal(t, uint64(5), *tags.MaxItems)
+		assert.Equal(t, "uuid", tags.Items.Value.Format)
+
+		website := schema.Properties["website"].Value
+		assert.Equal(t, "uri", website.Format)
+		assert.Equal(t, "startswith=https", website.Extensions["x-validate"])
+
+		// datetime is the format only for the layout of OpenAPI format
+		assert.Equal(t, "date", schema.Properties["born"].Value.Format)
+		assert.Equal(t, "date-time", schema.Properties["adopted"].Value.Format)
+
+		fed := schema.Properties["fed"].Value
+		assert.Empty(t, fed.Format)
+		assert.Equal(t, "datetime=15:04", fed.Extensions["x-validate"])
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		type Invalid struct {
+			Name string `json:"name" validate:"min=abc"`
+		}
+
+		reg := openapidoc.NewRegistry(openapidoc.WithValidatorTags())
+		err := reg.TryAdd(http.MethodPost, "/invalid",
+			request.NewRequest().Body("application/json", Invalid{}),
+			map[string]*response.Response{},
+		)
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, "Name", addErr.FieldPath)
+	})
+}
+
+func TestRegistrySchemaKeywords(t *testing.T) {
+	type Order struct {
+		Status   string   `json:"status" openapi3:"enum:placed;approved;delivered,default:placed,desc:Order status"`
+		Quantity int      `json:"quantity" openapi3:"min:1,max:100,exclusiveMin,default:1"`
+		Price    float64  `json:"price" openapi3:"format:double,enum:1.5;2.5"`
+		Notes    []string `json:"notes" openapi3:"maxItems:3,uniqueItems,maxLength:20"`
+		ShipDate string   `json:"ship_date" openapi3:"format:date,nullable,writeOnly"`
+		Legacy   bool     `json:"legacy" openapi3:"deprecated,title:Legacy flag"`
+	}
+
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/orders",
+		request.NewRequest().Body("application/json", Order{}),
+		map[string]*response.Response{
+			"201": response.NewResponse().Body("application/json", Order{}),
+		},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+
+	order := doc.Components.Schemas["openapidoc_test.Order"].Value
+	status := order.Properties["status"].Value
+	assert.Equal(t, []interface{}{"placed", "approved", "delivered"}, status.Enum)
+	assert.Equal(t, "placed", status.Default)
+	assert.Equal(t, "Order status", status.Description)
+
+	quantity := order.Properties["quantity"].Value
+	assert.Equal(t, float64(1), *quantity.Min)
+	assert.Equal(t, float64(100), *quantity.Max)
+	assert.True(t, quantity.ExclusiveMin)
+	assert.Equal(t, 1, quantity.Default)
+
+	assert.Equal(t, []interface{}{1.5, 2.5}, order.Properties["price"].Value.Enum)
+
+	// maxLength is applied to the items, because the tag is used for the items too
+	notes := order.Properties["notes"].Value
+	assert.Equal(t, uint64(3), *notes.MaxItems)
+	assert.True(t, notes.UniqueItems)
+	assert.Nil(t, notes.MaxLength)
+	assert.Equal(t, uint64(20), *notes.Items.Value.MaxLength)
+
+	shipDate := order.Properties["ship_date"].Value
+	assert.Equal(t, "date", shipDate.Format)
+	assert.True(t, shipDate.Nullable)
+	assert.True(t, shipDate.WriteOnly)
+
+	legacy := order.Properties["legacy"].Value
+	assert.True(t, legacy.Deprecated)
+	assert.Equal(t, "Legacy flag", legacy.Title)
+
+	t.Run("invalid value", func(t *testing.T) {
+		type Invalid struct {
+			Quantity int `json:"quantity" openapi3:"enum:1;two"`
+		}
+
+		err := openapidoc.NewRegistry().TryAdd(http.MethodPost, "/invalid",
+			request.NewRequest().Body("application/json", Invalid{}),
+			map[string]*response.Response{},
+		)
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, "Quantity", addErr.FieldPath)
+	})
+
+	t.Run("enum on array", func(t *testing.T) {
+		type Basket struct {
+			Sizes []*int `json:"sizes" openapi3:"enum:1;2;3"`
+		}
+
+		reg := openapidoc.NewRegistry()
+		err := reg.TryAdd(http.MethodPost, "/baskets",
+			request.NewRequest().Body("application/json", Basket{}),
+			map[string]*response.Response{},
+		)
+		assert.NoError(t, err)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+
+		sizes := doc.Components.Schemas["openapidoc_test.Basket"].Value.Properties["sizes"].Value
+		assert.Nil(t, sizes.Enum)
+		assert.Equal(t, []interface{}{1, 2, 3}, sizes.Items.Value.Enum)
+	})
+
+	t.Run("enum on object", func(t *testing.T) {
+		type Invalid struct {
+			Labels map[string]string `json:"labels" openapi3:"enum:a;b"`
+		}
+
+		err := openapidoc.NewRegistry().TryAdd(http.MethodPost, "/invalid",
+			request.NewRequest().Body("application/json", Invalid{}),
+			map[string]*response.Response{},
+		)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "enum is only for the scalar or the array of scalar")
+		}
+	})
+}
+
+func TestRegistryTagGrammar(t *testing.T) {
+	type Link struct {
+		URL  string `json:"url" openapi3:"ex:https://example.com/pets?id=1,desc:'Link, with comma: and colon'"`
+		Note string `json:"note" openapi3:"desc:it\\'s escaped\\, too,x-order:1"`
+	}
+
+	type Owner struct {
+		Link Link `json:"link"`
+	}
+
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/owners",
+		request.NewRequest().Body("application/json", Owner{}),
+		map[string]*response.Response{},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+
+	link := doc.Components.Schemas["openapidoc_test.Owner"].Value.Properties["link"].Value
+	assert.Equal(t, "https://example.com/pets?id=1", link.Properties["url"].Value.Example)
+	assert.Equal(t, "Link, with comma: and colon", link.Properties["url"].Value.Description)
+	assert.Equal(t, "it's escaped, too", link.Properties["note"].Value.Description)
+	assert.Equal(t, float64(1), link.Properties["note"].Value.Extensions["x-order"])
+
+	t.Run("error names the struct and field", func(t *testing.T) {
+		type Address struct {
+			Zip int `json:"zip" openapi3:"ex:abc"`
+		}
+
+		type Customer struct {
+			Address Address `json:"address"`
+		}
+
+		err := openapidoc.NewRegistry().TryAdd(http.MethodPost, "/customers",
+			request.NewRequest().Body("application/json", Customer{}),
+			map[string]*response.Response{},
+		)
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, "Address.Zip", addErr.FieldPath)
+		assert.Equal(t, "openapidoc_test.Address", addErr.Struct)
+		assert.Contains(t, err.Error(), "of struct openapidoc_test.Address")
+	})
+
+	t.Run("error names the field when nested structs share the field", func(t *testing.T) {
+		type Breeder struct {
+			Name string `json:"name" openapi3:"desc:Breeder name"`
+		}
+
+		type Vet struct {
+			Name string `json:"name" openapi3:"maxLength:abc"`
+		}
+
+		type Clinic struct {
+			Vet Vet `json:"vet"`
+		}
+
+		type Litter struct {
+			Breeder Breeder `json:"breeder"`
+			Clinic  Clinic  `json:"clinic"`
+		}
+
+		err := openapidoc.NewRegistry().TryAdd(http.MethodPost, "/litters",
+			request.NewRequest().Body("application/json", Litter{}),
+			map[string]*response.Response{},
+		)
+
+		var addErr *openapidoc.AddError
+		if assert.True(t, errors.As(err, &addErr)) {
+			assert.Equal(t, "Clinic.Vet.Name", addErr.FieldPath)
+			assert.Equal(t, "openapidoc_test.Vet", addErr.Struct)
+		}
+
+		// the field is written once
+		assert.Contains(t, err.Error(), "field Clinic.Vet.Name of struct openapidoc_test.Vet: ")
+		assert.NotContains(t, err.Error(), "field name")
+	})
+
+	t.Run("unterminated quote", func(t *testing.T) {
+		type Invalid struct {
+			Name string `json:"name" openapi3:"desc:'unterminated"`
+		}
+
+		err := openapidoc.NewRegistry().TryAdd(http.MethodPost, "/invalid",
+			request.NewRequest().Body("application/json", Invalid{}),
+			map[string]*response.Response{},
+		)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unterminated quote")
+		}
+	})
+
+	t.Run("apostrophe inside value", func(t *testing.T) {
+		type Owner struct {
+			Name string `json:"name" openapi3:"desc:Pet's owner name,ex:O'Brien"`
+		}
+
+		for _, reg := range []*openapidoc.Registry{openapidoc.NewRegistry(), openapidoc.NewRegistry(openapidoc.WithStrictTags())} {
+			err := reg.TryAdd(http.MethodPost, "/owners",
+				request.NewRequest().Body("application/json", Owner{}),
+				map[string]*response.Response{},
+			)
+			assert.NoError(t, err)
+
+			doc, err := reg.Generate()
+			assert.NoError(t, err)
+
+			name := doc.Components.Schemas["openapidoc_test.Owner"].Value.Properties["name"].Value
+			assert.Equal(t, "Pet's owner name", name.Description)
+			assert.Equal(t, "O'Brien", name.Example)
+		}
+	})
+
+	t.Run("integer out of range", func(t *testing.T) {
+		type Sizes struct {
+			Small uint8 `json:"small" openapi3:"ex:-300"`
+		}
+
+		err := openapidoc.NewRegistry().TryAdd(http.MethodPost, "/sizes",
+			request.NewRequest().Body("application/json", Sizes{}),
+			map[string]*response.Response{},
+		)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "invalid example value '-300'")
+		}
+
+		type Medium struct {
+			Medium int16 `json:"medium" openapi3:"default:40000"`
+		}
+
+		err = openapidoc.NewRegistry().TryAdd(http.MethodPost, "/medium",
+			request.NewRequest().Body("application/json", Medium{}),
+			map[string]*response.Response{},
+		)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "value out of range")
+		}
+
+		type Large struct {
+			Large int64  `json:"large" openapi3:"ex:9223372036854775807,enum:-1;0"`
+			Count uint16 `json:"count" openapi3:"ex:65535"`
+		}
+
+		assert.NoError(t, openapidoc.NewRegistry().TryAdd(http.MethodPost, "/large",
+			request.NewRequest().Body("application/json", Large{}),
+			map[string]*response.Response{},
+		))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		type Typo struct {
+			Name string `json:"name" openapi3:"descr:Pet name"`
+		}
+
+		add := func(reg *openapidoc.Registry) error {
+			return reg.TryAdd(http.MethodPost, "/typo",
+				request.NewRequest().Body("application/json", Typo{}),
+				map[string]*response.Response{},
+			)
+		}
+
+		assert.NoError(t, add(openapidoc.NewRegistry()))
+
+		err := add(openapidoc.NewRegistry(openapidoc.WithStrictTags()))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unknown openapi3 tag keyword 'descr'")
+		}
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, "Name", addErr.FieldPath)
+	})
+}
+
+func TestRegistryRequiredPolicy(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"created_by"`
+	}
+
+	type UpdatePet struct {
+		Audit
+		ID       int     `json:"id"`
+		Name     string  `json:"name,omitempty"`
+		Nickname *string `json:"nickname"`
+		Sound    string  `json:"sound" openapi3:"optional"`
+		Owner    *string `json:"owner" openapi3:"required"`
+	}
+
+	newRegistry := func(configs ...func(*openapidoc.Config)) *openapidoc.Registry {
+		reg := openapidoc.NewRegistry(configs...)
+		reg.Add(http.MethodPut, "/pets/{id}",
+			request.NewRequest().Body("application/json", UpdatePet{}),
+			map[string]*response.Response{},
+		)
+
+		return reg
+	}
+
+	t.Run("explicit by default", func(t *testing.T) {
+		doc, err := newRegistry().Generate()
+		assert.NoError(t, err)
+
+		schema := doc.Components.Schemas["openapidoc_test.UpdatePet"].Value
+		assert.Equal(t, []string{"owner"}, schema.Required)
+	})
+
+	t.Run("inferred", func(t *testing.T) {
+		doc, err := newRegistry(openapidoc.WithRequiredPolicy(utils.RequiredInferred)).Generate()
+		assert.NoError(t, err)
+
+		schema := doc.Components.Schemas["openapidoc_test.UpdatePet"].Value
+		assert.ElementsMatch(t, []string{"created_by", "id", "owner"}, schema.Required)
+	})
+
+	t.Run("optional overrides validate tag", func(t *testing.T) {
+		type CreatePet struct {
+			Name string `json:"name" validate:"required" openapi3:"optional"`
+			Kind string `json:"kind" validate:"required"`
+		}
+
+		reg := openapidoc.NewRegistry(openapidoc.WithValidatorTags())
+		reg.Add(http.MethodPost, "/pets",
+			request.NewRequest().Body("application/json", CreatePet{}),
+			map[string]*response.Response{},
+		)
+
+		doc, err := reg.Generate()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kind"}, doc.Components.Schemas["openapidoc_test.CreatePet"].Value.Required)
+	})
+
+	t.Run("unknown required property", func(t *testing.T) {
+		type Animal struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+
+		type Zoo struct {
+			Animal Animal `json:"animal" openapi3:"required:'id;sound'"`
+		}
+
+		err := openapidoc.NewRegistry().TryAdd(http.MethodPost, "/zoos",
+			request.NewRequest().Body("application/json", Zoo{}),
+			map[string]*response.Response{},
+		)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "required property 'sound' does not exist")
+		}
+
+		var addErr *openapidoc.AddError
+		assert.True(t, errors.As(err, &addErr))
+		assert.Equal(t, "Animal", addErr.FieldPath)
+	})
+}
+
+type PetKind string
+
+const (
+	PetKindCat PetKind = "cat"
+	PetKindDog PetKind = "dog"
+)
+
+func (PetKind) OpenAPIEnum() []interface{} {
+	return []interface{}{PetKindCat, PetKindDog}
+}
+
+type PetStatus int
+
+const (
+	PetStatusAvailable PetStatus = iota + 1
+	PetStatusSold
+)
+
+func (*PetStatus) OpenAPIEnumValues() []utils.EnumValue {
+	return []utils.EnumValue{
+		{Value: PetStatusAvailable, VarName: "PetStatusAvailable", Description: "Ready to be adopted"},
+		{Value: PetStatusSold, VarName: "PetStatusSold"},
+	}
+}
+
+func TestRegistryEnum(t *testing.T) {
+	type Listing struct {
+		Kind     PetKind            `json:"kind"`
+		Previous *PetKind           `json:"previous"`
+		Accepted []PetKind          `json:"accepted"`
+		Owners   map[string]PetKind `json:"owners"`
+		Status   PetStatus          `json:"status"`
+		Other    PetKind            `json:"other" openapi3:"enum:cat"`
+	}
+
+	reg := openapidoc.NewRegistry()
+	reg.Add(http.MethodPost, "/listings",
+		request.NewRequest().Body("application/json", Listing{}),
+		map[string]*response.Response{},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+
+	kinds := []interface{}{"cat", "dog"}
+	listing := doc.Components.Schemas["openapidoc_test.Listing"].Value
+	assert.Equal(t, kinds, listing.Properties["kind"].Value.Enum)
+	assert.Equal(t, kinds, listing.Properties["previous"].Value.Enum)
+	assert.Equal(t, kinds, listing.Properties["accepted"].Value.Items.Value.Enum)
+	assert.Equal(t, kinds, listing.Properties["owners"].Value.AdditionalProperties.Value.Enum)
+
+	// the explicit tag overrides the interface
+	assert.Equal(t, []interface{}{"cat"}, listing.Properties["other"].Value.Enum)
+
+	status := listing.Properties["status"].Value
+	assert.Equal(t, []interface{}{float64(1), float64(2)}, status.Enum)
+	assert.Equal(t, []interface{}{"PetStatusAvailable", "PetStatusSold"}, status.Extensions[utils.EnumVarNamesExtension])
+	assert.Equal(t, []interface{}{"Ready to be adopted", ""}, status.Extensions[utils.EnumDescriptionsExtension])
+}
+
+func TestRegistryConstEnums(t *testing.T) {
+	type Adoption struct {
+		Kind     *testasset.PetKind            `json:"kind"`
+		Priority map[string]testasset.Priority `json:"priority"`
+	}
+
+	reg := openapidoc.NewRegistry(openapidoc.WithConstEnums())
+	reg.Add(http.MethodPost, "/adoptions",
+		request.NewRequest().Body("application/json", Adoption{}),
+		map[string]*response.Response{},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+
+	adoption := doc.Components.Schemas["openapidoc_test.Adoption"].Value
+	kind := adoption.Properties["kind"].Value
+	assert.Equal(t, []interface{}{"cat", "dog"}, kind.Enum)
+	assert.Equal(t, []interface{}{"PetKindCat is the cat.", "PetKindDog is the dog."}, kind.Extensions[utils.EnumDescriptionsExtension])
+	assert.Equal(t, []interface{}{float64(1), float64(2), float64(10)}, adoption.Properties["priority"].Value.AdditionalProperties.Value.Enum)
+}
+
+type Money int64
+
+func (Money) OpenAPISchema() *openapi3.Schema {
+	return &openapi3.Schema{Type: "string", Format: "decimal", Pattern: `^-?[0-9]+\.[0-9]{2}$`}
+}
+
+func TestRegistryTypeSchema(t *testing.T) {
+	type Invoice struct {
+		Total    Money      `json:"total" openapi3:"desc:Total price"`
+		Lines    []Money    `json:"lines"`
+		Discount *Money     `json:"discount"`
+		Balance  big.Int    `json:"balance"`
+		Reserved []*big.Int `json:"reserved"`
+	}
+
+	reg := openapidoc.NewRegistry(openapidoc.WithTypeSchema(big.Int{}, &openapi3.Schema{Type: "string", Pattern: "^-?[0-9]+$"}))
+	reg.Add(http.MethodPost, "/invoices",
+		request.NewRequest().Body("application/json", Invoice{}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Money(0)),
+		},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+
+	invoice := doc.Components.Schemas["openapidoc_test.Invoice"].Value
+	total := invoice.Properties["total"].Value
+	assert.Equal(t, "string", total.Type)
+	assert.Equal(t, "decimal", total.Format)
+	assert.Equal(t, "Total price", total.Description)
+	assert.Equal(t, "decimal", invoice.Properties["lines"].Value.Items.Value.Format)
+	assert.Equal(t, "decimal", invoice.Properties["discount"].Value.Format)
+
+	balance := invoice.Properties["balance"].Value
+	assert.Equal(t, "string", balance.Type)
+	assert.Equal(t, "^-?[0-9]+$", balance.Pattern)
+	assert.Empty(t, balance.Properties)
+	assert.Equal(t, "string", invoice.Properties["reserved"].Value.Items.Value.Type)
+
+	assert.Equal(t, "decimal", doc.Components.Schemas["openapidoc_test.Money"].Value.Format)
+}
+
+func TestRegistryTypeSchemaFields(t *testing.T) {
+	// the fields of the provided type is not checked, so the invalid and unknown keyword is ignored
+	type Coordinate struct {
+		Lat float64 `json:"lat" openapi3:"min:abc"`
+		Lng float64 `json:"lng" openapi3:"descr:Longitude"`
+	}
+
+	type Place struct {
+		Location Coordinate   `json:"location"`
+		Route    []Coordinate `json:"route"`
+		Zoom     int          `json:"zoom"`
+	}
+
+	reg := openapidoc.NewRegistry(
+		openapidoc.WithStrictTags(),
+		openapidoc.WithTypeSchema(Coordinate{}, &openapi3.Schema{Type: "string", Pattern: "^-?[0-9.]+,-?[0-9.]+$"}),
+	)
+
+	err := reg.TryAdd(http.MethodPost, "/places",
+		request.NewRequest().Body("application/json", Place{}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Coordinate{}),
+		},
+	)
+	assert.NoError(t, err)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+
+	place := doc.Components.Schemas["openapidoc_test.Place"].Value
+	assert.Equal(t, "string", place.Properties["location"].Value.Type)
+	assert.Empty(t, place.Properties["location"].Value.Properties)
+	assert.Equal(t, "string", place.Properties["route"].Value.Items.Value.Type)
+	assert.Equal(t, "string", doc.Components.Schemas["openapidoc_test.Coordinate"].Value.Type)
+
+	// the same fields outside the provided type is still checked
+	type Area struct {
+		Center Coordinate `json:"center"`
+		Radius float64    `json:"radius" openapi3:"min:abc"`
+	}
+
+	err = reg.TryAdd(http.MethodPost, "/areas",
+		request.NewRequest().Body("application/json", Area{}),
+		map[string]*response.Response{
+			"200": response.NewResponse(),
+		},
+	)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid min value 'abc'")
+	}
+}
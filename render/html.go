@@ -0,0 +1,168 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"html/template"
+)
+
+// defaultHTMLTmpl is self-contained page, the style is inline and no script or external assets is used.
+const defaultHTMLTmpl = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #24292f; display: flex; }
+nav { width: 280px; flex-shrink: 0; height: 100vh; overflow-y: auto; position: sticky; top: 0; background: #f6f8fa; border-right: 1px solid #d0d7de; padding: 16px; box-sizing: border-box; font-size: 14px; }
+nav ul { list-style: none; padding-left: 0; }
+nav li { margin: 4px 0; }
+nav a { color: #0969da; text-decoration: none; }
+main { padding: 24px 40px; max-width: 1100px; flex-grow: 1; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0 16px; font-size: 14px; }
+th, td { border: 1px solid #d0d7de; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+pre { background: #f6f8fa; padding: 12px; overflow-x: auto; border-radius: 6px; }
+code { font-family: SFMono-Regular, Consolas, monospace; }
+.operation { border-top: 1px solid #d0d7de; padding-top: 8px; margin-top: 24px; }
+.method { display: inline-block; min-width: 60px; padding: 2px 6px; border-radius: 4px; color: #fff; background: #6e7781; text-align: center; font-size: 13px; }
+.method-get { background: #1f883d; }
+.method-post { background: #0969da; }
+.method-put, .method-patch { background: #9a6700; }
+.method-delete { background: #cf222e; }
+.deprecated { color: #cf222e; font-weight: bold; }
+</style>
+</head>
+<body>
+<nav>
+<strong>{{ .Title }}</strong>
+{{- range .Tags }}
+<p>{{ .Name }}</p>
+<ul>
+{{- range .Operations }}
+<li><a href="#{{ .ID }}"><span class="method method-{{ lower .Method }}">{{ .Method }}</span> {{ .Path }}</a></li>
+{{- end }}
+</ul>
+{{- end }}
+</nav>
+<main>
+<h1>{{ .Title }}</h1>
+{{- with .Version }}
+<p>Version: {{ . }}</p>
+{{- end }}
+{{- with .Description }}
+<p>{{ . }}</p>
+{{- end }}
+{{- with .Servers }}
+<h2>Servers</h2>
+<table>
+<tr><th>URL</th><th>Description</th></tr>
+{{- range . }}
+<tr><td><code>{{ .URL }}</code></td><td>{{ .Description }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- range .Tags }}
+<h2>{{ .Name }}</h2>
+{{- with .Description }}
+<p>{{ . }}</p>
+{{- end }}
+<table>
+<tr><th>Method</th><th>Path</th><th>Operation ID</th><th>Summary</th></tr>
+{{- range .Operations }}
+<tr><td>{{ .Method }}</td><td><a href="#{{ .ID }}">{{ .Path }}</a></td><td>{{ .OperationID }}</td><td>{{ .Summary }}</td></tr>
+{{- end }}
+</table>
+{{- range .Operations }}
+<section class="operation" id="{{ .ID }}">
+<h3><span class="method method-{{ lower .Method }}">{{ .Method }}</span> <code>{{ .Path }}</code></h3>
+{{- if .Deprecated }}
+<p class="deprecated">Deprecated</p>
+{{- end }}
+{{- with .Summary }}
+<p>{{ . }}</p>
+{{- end }}
+{{- with .Description }}
+<p>{{ . }}</p>
+{{- end }}
+{{- with .Parameters }}
+<h4>Parameters</h4>
+<table>
+<tr><th>Name</th><th>In</th><th>Type</th><th>Required</th><th>Description</th><th>Example</th></tr>
+{{- range . }}
+<tr><td><code>{{ .Name }}</code></td><td>{{ .In }}</td><td>{{ .Type }}</td><td>{{ yesNo .Required }}</td><td>{{ .Description }}</td><td><code>{{ .Example }}</code></td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- range .RequestBody }}
+<h4>Request body <code>{{ .ContentType }}</code></h4>
+{{ template "body" . }}
+{{- end }}
+{{- with .Responses }}
+<h4>Responses</h4>
+<table>
+<tr><th>Status</th><th>Description</th></tr>
+{{- range . }}
+<tr><td>{{ .Status }}</td><td>{{ .Description }}</td></tr>
+{{- end }}
+</table>
+{{- range . }}
+{{- $status := .Status }}
+{{- with .Headers }}
+<h5>{{ $status }} headers</h5>
+<table>
+<tr><th>Name</th><th>Type</th><th>Description</th><th>Example</th></tr>
+{{- range . }}
+<tr><td><code>{{ .Name }}</code></td><td>{{ .Type }}</td><td>{{ .Description }}</td><td><code>{{ .Example }}</code></td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- range .Bodies }}
+<h5>{{ $status }} <code>{{ .ContentType }}</code></h5>
+{{ template "body" . }}
+{{- end }}
+{{- end }}
+{{- end }}
+</section>
+{{- end }}
+{{- end }}
+</main>
+</body>
+</html>
+{{ define "body" }}
+{{- with .Properties }}
+<table>
+<tr><th>Property</th><th>Type</th><th>Required</th><th>Description</th><th>Enum</th></tr>
+{{- range . }}
+<tr><td><code>{{ with .Path }}{{ . }}{{ else }}(body){{ end }}</code></td><td>{{ .Type }}</td><td>{{ yesNo .Required }}</td><td>{{ .Description }}</td><td>{{ .Enum }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- with .Example }}
+<p>Example:</p>
+<pre><code>{{ . }}</code></pre>
+{{- end }}
+{{- end }}`
+
+// HTML renders the whole document as single-page HTML, the page has no external assets.
+func (r *Renderer) HTML(doc *openapi3.T) ([]byte, error) {
+	data, err := NewDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New("html").Funcs(r.funcs).Parse(r.htmlTmpl)
+	if err != nil {
+		return nil, fmt.Errorf("render: cannot parse html template: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, data)
+	if err != nil {
+		return nil, fmt.Errorf("render: cannot execute html template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
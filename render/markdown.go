@@ -0,0 +1,200 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"strings"
+	"text/template"
+)
+
+const defaultMarkdownTmpl = `# {{ .Title }}
+{{- with .Version }}
+
+Version: {{ . }}
+{{- end }}
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+{{- with .Servers }}
+
+## Servers
+
+| URL | Description |
+| --- | --- |
+{{- range . }}
+| {{ cell .URL }} | {{ cell .Description }} |
+{{- end }}
+{{- end }}
+{{- range .Tags }}
+
+## {{ .Name }}
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+
+| Method | Path | Operation ID | Summary |
+| --- | --- | --- | --- |
+{{- range .Operations }}
+| {{ .Method }} | [{{ cell .Path }}](#{{ .ID }}) | {{ cell .OperationID }} | {{ cell .Summary }} |
+{{- end }}
+{{- range .Operations }}
+
+### {{ .Method }} {{ .Path }}
+{{- if .Deprecated }}
+
+**Deprecated**
+{{- end }}
+{{- with .Summary }}
+
+{{ . }}
+{{- end }}
+{{- with .Description }}
+
+{{ . }}
+{{- end }}
+{{- with .Parameters }}
+
+#### Parameters
+
+| Name | In | Type | Required | Description | Example |
+| --- | --- | --- | --- | --- | --- |
+{{- range . }}
+| {{ cell .Name }} | {{ .In }} | {{ cell .Type }} | {{ yesNo .Required }} | {{ cell .Description }} | {{ cell .Example }} |
+{{- end }}
+{{- end }}
+{{- range .RequestBody }}
+
+#### Request body ` + "`{{ .ContentType }}`" + `
+{{ template "body" . }}
+{{- end }}
+{{- with .Responses }}
+
+#### Responses
+
+| Status | Description |
+| --- | --- |
+{{- range . }}
+| {{ .Status }} | {{ cell .Description }} |
+{{- end }}
+{{- range . }}
+{{- $status := .Status }}
+{{- with .Headers }}
+
+##### {{ $status }} headers
+
+| Name | Type | Description | Example |
+| --- | --- | --- | --- |
+{{- range . }}
+| {{ cell .Name }} | {{ cell .Type }} | {{ cell .Description }} | {{ cell .Example }} |
+{{- end }}
+{{- end }}
+{{- range .Bodies }}
+
+##### {{ $status }} ` + "`{{ .ContentType }}`" + `
+{{ template "body" . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{ define "body" }}
+{{- with .Properties }}
+| Property | Type | Required | Description | Enum |
+| --- | --- | --- | --- | --- |
+{{- range . }}
+| {{ with .Path }}{{ cell . }}{{ else }}(body){{ end }} | {{ cell .Type }} | {{ yesNo .Required }} | {{ cell .Description }} | {{ cell .Enum }} |
+{{- end }}
+{{- end }}
+{{- with .Example }}
+
+Example:
+
+` + "```" + `{{ codeLang $.ContentType }}
+{{ . }}
+` + "```" + `
+{{- end }}
+{{- end }}`
+
+func defaultFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		// cell escapes the value to be written in the Markdown table cell
+		"cell": func(s string) string {
+			s = strings.ReplaceAll(s, "|", `\|`)
+			s = strings.ReplaceAll(s, "\r\n", "<br>")
+			return strings.ReplaceAll(s, "\n", "<br>")
+		},
+		"yesNo": func(b bool) string {
+			if b {
+				return "yes"
+			}
+
+			return "no"
+		},
+		"codeLang": func(contentType string) string {
+			switch {
+			case strings.Contains(contentType, "json"):
+				return "json"
+			case strings.Contains(contentType, "xml"):
+				return "xml"
+			default:
+				return ""
+			}
+		},
+		"lower": strings.ToLower,
+	}
+}
+
+// Markdown renders the whole document as a single Markdown file.
+func (r *Renderer) Markdown(doc *openapi3.T) ([]byte, error) {
+	data, err := NewDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.executeMarkdown(data)
+}
+
+// MarkdownPerTag renders one Markdown file per tag.
+// The returned map k = file name, i.e: pets.md, v = the Markdown of the operations with that tag.
+// Operations without tag are written in default.md
+func (r *Renderer) MarkdownPerTag(doc *openapi3.T) (map[string][]byte, error) {
+	data, err := NewDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(data.Tags))
+	for _, tag := range data.Tags {
+		tagData := *data
+		tagData.Tags = []Tag{tag}
+
+		out, err := r.executeMarkdown(&tagData)
+		if err != nil {
+			return nil, err
+		}
+
+		files[utils.SanitizeComponentName(tag.Name)+".md"] = out
+	}
+
+	return files, nil
+}
+
+func (r *Renderer) executeMarkdown(data *Document) ([]byte, error) {
+	tmpl, err := template.New("markdown").Funcs(r.funcs).Parse(r.markdownTmpl)
+	if err != nil {
+		return nil, fmt.Errorf("render: cannot parse markdown template: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, data)
+	if err != nil {
+		return nil, fmt.Errorf("render: cannot execute markdown template: %w", err)
+	}
+
+	out := bytes.TrimSpace(buf.Bytes())
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,496 @@
+// Package render writes the OpenAPI document as static documentation:
+// Markdown (single file or one file per tag) and self-contained single-page HTML without external assets.
+//
+// The templates receive Document, so the default template can be replaced using WithMarkdownTemplate or WithHTMLTemplate,
+// i.e: to match the wiki style.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/internal/docutil"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"sort"
+	"strings"
+)
+
+// maxPropertyDepth limits the flattened properties, so recursive schema is not infinite loop.
+const maxPropertyDepth = 8
+
+// untagged is the tag name of the operations without tag.
+const untagged = "default"
+
+// Document is the data passed to the templates.
+type Document struct {
+	Title       string
+	Version     string
+	Description string
+	Servers     []Server
+	Tags        []Tag
+}
+
+type Server struct {
+	URL         string
+	Description string
+}
+
+// Tag is the operations grouped by the first operation tag.
+type Tag struct {
+	Name        string
+	Description string
+	Operations  []Operation
+}
+
+type Operation struct {
+	// ID is the unique anchor of the operation, i.e: get-pets-id
+	ID          string
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Parameters  []Parameter
+	RequestBody []Body
+	Responses   []Response
+}
+
+type Parameter struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+	Example     string
+}
+
+// Body is the payload of one content type.
+type Body struct {
+	ContentType string
+	Properties  []Property
+
+	// Example is the indented JSON of the first named example, the media type example, or the example built from the schema.
+	Example string
+}
+
+type Response struct {
+	Status      string
+	Description string
+	Headers     []Parameter
+	Bodies      []Body
+}
+
+// Property is the flattened schema property, nested property is written using path, i.e: owner.address.city or tags[].name
+type Property struct {
+	Path        string
+	Type        string
+	Required    bool
+	Description string
+	Enum        string
+}
+
+// Opt is the option of the Renderer.
+type Opt func(*Renderer) error
+
+// WithMarkdownTemplate replaces the default Markdown template, the template is executed using Document.
+func WithMarkdownTemplate(tmpl string) Opt {
+	return func(r *Renderer) error {
+		if strings.TrimSpace(tmpl) == "" {
+			return fmt.Errorf("render: markdown template must not be empty")
+		}
+
+		r.markdownTmpl = tmpl
+		return nil
+	}
+}
+
+// WithHTMLTemplate replaces the default HTML template, the template is executed using Document.
+// The template is parsed using html/template, so the value is escaped.
+func WithHTMLTemplate(tmpl string) Opt {
+	return func(r *Renderer) error {
+		if strings.TrimSpace(tmpl) == "" {
+			return fmt.Errorf("render: html template must not be empty")
+		}
+
+		r.htmlTmpl = tmpl
+		return nil
+	}
+}
+
+// WithFuncs adds the template functions, it replaces the default function with the same name.
+func WithFuncs(funcs map[string]interface{}) Opt {
+	return func(r *Renderer) error {
+		for name, fn := range funcs {
+			r.funcs[name] = fn
+		}
+
+		return nil
+	}
+}
+
+// Renderer renders the document using the templates.
+type Renderer struct {
+	markdownTmpl string
+	htmlTmpl     string
+	funcs        map[string]interface{}
+}
+
+func NewRenderer(options ...Opt) (*Renderer, error) {
+	r := &Renderer{
+		markdownTmpl: defaultMarkdownTmpl,
+		htmlTmpl:     defaultHTMLTmpl,
+		funcs:        defaultFuncs(),
+	}
+
+	for _, option := range options {
+		if option != nil {
+			err := option(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// NewDocument builds the template data from the OpenAPI document.
+func NewDocument(doc *openapi3.T) (*Document, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("render: document must not be nil")
+	}
+
+	// load the document again, so all references value is resolved
+	docJSON, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = openapi3.NewLoader().LoadFromData(docJSON)
+	if err != nil {
+		return nil, fmt.Errorf("render: cannot load the document: %w", err)
+	}
+
+	out := &Document{}
+	if doc.Info != nil {
+		out.Title = doc.Info.Title
+		out.Version = doc.Info.Version
+		out.Description = doc.Info.Description
+	}
+
+	if out.Title == "" {
+		out.Title = "API"
+	}
+
+	for _, server := range doc.Servers {
+		if server != nil {
+			out.Servers = append(out.Servers, Server{URL: server.URL, Description: server.Description})
+		}
+	}
+
+	// tagDescriptions map k = tag name, v = description in the document tags
+	tagDescriptions := make(map[string]string)
+	for _, tag := range doc.Tags {
+		if tag != nil {
+			tagDescriptions[tag.Name] = tag.Description
+		}
+	}
+
+	// tags map k = tag name, v = index in out.Tags
+	tags := make(map[string]int)
+	for _, path := range docutil.SortedKeys(doc.Paths) {
+		pathItem := doc.Paths[path]
+		operations := pathItem.Operations()
+		for _, method := range docutil.SortedKeys(operations) {
+			op := operations[method]
+
+			tagName := untagged
+			if len(op.Tags) > 0 {
+				tagName = op.Tags[0]
+			}
+
+			idx, exist := tags[tagName]
+			if !exist {
+				idx = len(out.Tags)
+				tags[tagName] = idx
+				out.Tags = append(out.Tags, Tag{Name: tagName, Description: tagDescriptions[tagName]})
+			}
+
+			out.Tags[idx].Operations = append(out.Tags[idx].Operations, newOperation(method, path, pathItem, op))
+		}
+	}
+
+	sort.SliceStable(out.Tags, func(i, j int) bool {
+		return out.Tags[i].Name < out.Tags[j].Name
+	})
+
+	return out, nil
+}
+
+func newOperation(method, path string, pathItem *openapi3.PathItem, op *openapi3.Operation) Operation {
+	out := Operation{
+		ID:          anchor(method + " " + path),
+		Method:      method,
+		Path:        path,
+		OperationID: op.OperationID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+	}
+
+	// operation parameters override the path item parameters with the same location and name
+	params := make(map[string]*openapi3.Parameter)
+	for _, paramRefs := range []openapi3.Parameters{pathItem.Parameters, op.Parameters} {
+		for _, paramRef := range paramRefs {
+			if paramRef != nil && paramRef.Value != nil {
+				params[paramRef.Value.In+"."+paramRef.Value.Name] = paramRef.Value
+			}
+		}
+	}
+
+	for _, key := range docutil.SortedKeys(params) {
+		out.Parameters = append(out.Parameters, newParameter(params[key].Name, params[key]))
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		out.RequestBody = newBodies(op.RequestBody.Value.Content)
+	}
+
+	for _, status := range docutil.SortedKeys(op.Responses) {
+		respRef := op.Responses[status]
+		if respRef == nil || respRef.Value == nil {
+			continue
+		}
+
+		resp := Response{Status: status, Bodies: newBodies(respRef.Value.Content)}
+		if respRef.Value.Description != nil {
+			resp.Description = *respRef.Value.Description
+		}
+
+		for _, name := range docutil.SortedKeys(respRef.Value.Headers) {
+			headerRef := respRef.Value.Headers[name]
+			if headerRef != nil && headerRef.Value != nil {
+				resp.Headers = append(resp.Headers, newParameter(name, &headerRef.Value.Parameter))
+			}
+		}
+
+		out.Responses = append(out.Responses, resp)
+	}
+
+	return out
+}
+
+func newParameter(name string, param *openapi3.Parameter) Parameter {
+	out := Parameter{
+		Name:        name,
+		In:          param.In,
+		Required:    param.Required,
+		Description: param.Description,
+	}
+
+	var example interface{}
+	if param.Example != nil {
+		example = param.Example
+	}
+
+	if param.Schema != nil && param.Schema.Value != nil {
+		out.Type = schemaType(param.Schema.Value)
+		if out.Description == "" {
+			out.Description = param.Schema.Value.Description
+		}
+
+		if example == nil {
+			example = param.Schema.Value.Example
+		}
+	}
+
+	if example != nil {
+		out.Example = compactJSON(example)
+	}
+
+	return out
+}
+
+func newBodies(content openapi3.Content) []Body {
+	bodies := make([]Body, 0, len(content))
+	for _, contentType := range docutil.SortedKeys(content) {
+		mediaType := content[contentType]
+		if mediaType == nil {
+			continue
+		}
+
+		body := Body{ContentType: contentType}
+
+		var schema *openapi3.Schema
+		if mediaType.Schema != nil {
+			schema = mediaType.Schema.Value
+			body.Properties = flatten(schema)
+		}
+
+		var example interface{}
+		examples := mediaType.Examples
+		for _, name := range docutil.SortedKeys(examples) {
+			if examples[name] != nil && examples[name].Value != nil {
+				example = examples[name].Value.Value
+				break
+			}
+		}
+
+		if example == nil {
+			example = mediaType.Example
+		}
+
+		if example == nil {
+			example = utils.SchemaExample(schema)
+		}
+
+		if example != nil {
+			body.Example = indentJSON(example)
+		}
+
+		bodies = append(bodies, body)
+	}
+
+	return bodies
+}
+
+// flatten returns the properties of the schema with nested property path, i.e: owner.name or tags[].name
+// If the schema is not object or array of object, it returns one property with empty path.
+func flatten(schema *openapi3.Schema) []Property {
+	if schema == nil {
+		return nil
+	}
+
+	props := make([]Property, 0)
+	switch {
+	case len(schema.Properties) > 0:
+		flattenProperties(&props, "", schema, map[*openapi3.Schema]bool{}, 0)
+	case schema.Type == "array" && schema.Items != nil && schema.Items.Value != nil && len(schema.Items.Value.Properties) > 0:
+		flattenProperties(&props, "[]", schema.Items.Value, map[*openapi3.Schema]bool{}, 0)
+	default:
+		props = append(props, newProperty("", schema, false))
+	}
+
+	return props
+}
+
+func flattenProperties(props *[]Property, prefix string, schema *openapi3.Schema, visiting map[*openapi3.Schema]bool, depth int) {
+	if schema == nil || visiting[schema] || depth > maxPropertyDepth {
+		return
+	}
+
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	for _, name := range docutil.SortedKeys(schema.Properties) {
+		propRef := schema.Properties[name]
+		if propRef == nil || propRef.Value == nil {
+			continue
+		}
+
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		prop := propRef.Value
+		*props = append(*props, newProperty(path, prop, required[name]))
+
+		switch {
+		case len(prop.Properties) > 0:
+			flattenProperties(props, path, prop, visiting, depth+1)
+		case prop.Type == "array" && prop.Items != nil && prop.Items.Value != nil:
+			flattenProperties(props, path+"[]", prop.Items.Value, visiting, depth+1)
+		}
+	}
+}
+
+func newProperty(path string, schema *openapi3.Schema, required bool) Property {
+	prop := Property{
+		Path:        path,
+		Type:        schemaType(schema),
+		Required:    required,
+		Description: schema.Description,
+	}
+
+	if len(schema.Enum) > 0 {
+		enum := make([]string, 0, len(schema.Enum))
+		for _, v := range schema.Enum {
+			enum = append(enum, compactJSON(v))
+		}
+
+		prop.Enum = strings.Join(enum, ", ")
+	}
+
+	return prop
+}
+
+// schemaType returns the type with format, i.e: integer(int64) or array of string
+func schemaType(schema *openapi3.Schema) string {
+	typ := schema.Type
+	if typ == "array" && schema.Items != nil && schema.Items.Value != nil {
+		typ = "array of " + schemaType(schema.Items.Value)
+	}
+
+	if schema.Format != "" {
+		typ = fmt.Sprintf("%s(%s)", typ, schema.Format)
+	}
+
+	if schema.Nullable {
+		typ += ", nullable"
+	}
+
+	return typ
+}
+
+func compactJSON(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+func indentJSON(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+// anchor returns lower case string with every non-alphanumeric character replaced by dash, i.e: get-pets-id
+func anchor(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,136 @@
+package render_test
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc"
+	"github.com/yusufsyaifudin/openapidoc/render"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type Owner struct {
+	Name string `json:"name" openapi3:"desc:Owner name|pipe"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type Pet struct {
+	ID    int    `json:"id" openapi3:"ex:2"`
+	Owner Owner  `json:"owner" openapi3:"required:name"`
+	Tags  []Tag  `json:"tags"`
+	Kind  string `json:"kind"`
+}
+
+func newDocument(t *testing.T) *openapi3.T {
+	reg := openapidoc.NewRegistry(
+		openapidoc.WithServerInfo(&openapi3.Info{Title: "Pet Store", Version: "v1.0.0"}),
+		openapidoc.WithServers(openapi3.Servers{{URL: "https://api.example.com"}}),
+	)
+
+	reg.Add(http.MethodPost, "/pets",
+		request.NewRequest().Body("application/json", Pet{}, request.WithExamples(
+			request.Example{Name: "kitty", Value: Pet{ID: 1, Kind: "cat"}},
+		)),
+		map[string]*response.Response{
+			"201": response.NewResponse().Description("Created").Body("application/json", Pet{}),
+		},
+		openapidoc.WithOperationID("createPet"),
+		openapidoc.WithOperationTags("pets"),
+	)
+
+	reg.Add(http.MethodGet, "/pets/{id}",
+		request.NewRequest().PathParams(request.PathParam{Name: "id", Value: 7, Description: "Pet ID"}),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("application/json", Pet{}),
+		},
+		openapidoc.WithOperationID("getPet"),
+		openapidoc.WithOperationTags("pets"),
+	)
+
+	reg.Add(http.MethodGet, "/health",
+		request.NewRequest(),
+		map[string]*response.Response{
+			"200": response.NewResponse().Body("text/plain", "OK"),
+		},
+	)
+
+	doc, err := reg.Generate()
+	assert.NoError(t, err)
+	return doc
+}
+
+func TestMarkdown(t *testing.T) {
+	r, err := render.NewRenderer()
+	assert.NoError(t, err)
+
+	b, err := r.Markdown(newDocument(t))
+	assert.NoError(t, err)
+
+	out := string(b)
+	assert.True(t, strings.HasPrefix(out, "# Pet Store\n\nVersion: v1.0.0\n"))
+	assert.Contains(t, out, "| https://api.example.com |  |")
+	assert.Contains(t, out, "\n## default\n")
+	assert.Contains(t, out, "\n## pets\n")
+	assert.Contains(t, out, "| POST | [/pets](#post-pets) | createPet |  |")
+	assert.Contains(t, out, "\n### GET /pets/{id}\n")
+	assert.Contains(t, out, "| id | path | integer | yes | Pet ID | 7 |")
+	assert.Contains(t, out, "#### Request body `application/json`")
+	assert.Contains(t, out, "| owner.name | string | yes | Owner name\\|pipe |  |")
+	assert.Contains(t, out, "| tags[].name | string | no |  |  |")
+	assert.Contains(t, out, "| kind | string | no |  |  |")
+	assert.Contains(t, out, "| 201 | Created |")
+	assert.Contains(t, out, "```json\n{\n  \"id\": 1,")
+	assert.Contains(t, out, "##### 200 `text/plain`")
+}
+
+func TestMarkdownPerTag(t *testing.T) {
+	r, err := render.NewRenderer()
+	assert.NoError(t, err)
+
+	files, err := r.MarkdownPerTag(newDocument(t))
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Contains(t, string(files["pets.md"]), "### POST /pets")
+	assert.NotContains(t, string(files["pets.md"]), "/health")
+	assert.Contains(t, string(files["default.md"]), "### GET /health")
+}
+
+func TestMarkdownTemplateOverride(t *testing.T) {
+	r, err := render.NewRenderer(
+		render.WithMarkdownTemplate(`{{ range .Tags }}{{ range .Operations }}* {{ shout .Method }} {{ .Path }}
+{{ end }}{{ end }}`),
+		render.WithFuncs(map[string]interface{}{
+			"shout": func(s string) string { return s + "!" },
+		}),
+	)
+	assert.NoError(t, err)
+
+	b, err := r.Markdown(newDocument(t))
+	assert.NoError(t, err)
+	assert.Equal(t, "* GET! /health\n* POST! /pets\n* GET! /pets/{id}\n", string(b))
+
+	_, err = render.NewRenderer(render.WithMarkdownTemplate(" "))
+	assert.Error(t, err)
+}
+
+func TestHTML(t *testing.T) {
+	r, err := render.NewRenderer()
+	assert.NoError(t, err)
+
+	b, err := r.HTML(newDocument(t))
+	assert.NoError(t, err)
+
+	out := string(b)
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(t, out, `<section class="operation" id="get-pets-id">`)
+	assert.Contains(t, out, "<td><code>owner.name</code></td>")
+	assert.Contains(t, out, "&#34;id&#34;: 1")
+	assert.NotContains(t, out, "<script")
+	assert.NotContains(t, out, `<link`)
+}
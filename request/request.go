@@ -14,6 +14,16 @@ type PathParam struct {
 	Name        string
 	Value       interface{}
 	Description string
+	Extensions  map[string]interface{}
+}
+
+// QueryParam is the query string parameter, i.e: ?page=1
+type QueryParam struct {
+	Name        string
+	Value       interface{}
+	Description string
+	Required    bool
+	Extensions  map[string]interface{}
 }
 
 type Request struct {
@@ -26,18 +36,25 @@ type Request struct {
 	// pathParams path parameters
 	pathParams []PathParam
 
+	// queryParams query string parameters
+	queryParams []QueryParam
+
 	// descriptions request description
 	descriptions []string
 
 	// required to mark whether this body payload is required or not
 	required bool
+
+	// extensions vendor extensions of the request body
+	extensions map[string]interface{}
 }
 
 func NewRequest() *Request {
 	return &Request{
-		bodies:     map[string]body{},
-		headers:    make([]*header.Header, 0),
-		pathParams: make([]PathParam, 0),
+		bodies:      map[string]body{},
+		headers:     make([]*header.Header, 0),
+		pathParams:  make([]PathParam, 0),
+		queryParams: make([]QueryParam, 0),
 	}
 }
 
@@ -69,6 +86,13 @@ func (r *Request) PathParams(params ...PathParam) *Request {
 	return r
 }
 
+// QueryParams adds the query string parameters, each called appends to the previous one.
+// The schema type is taken from the Value, i.e: 10 is integer and "cat" is string, and Value is written as the example.
+func (r *Request) QueryParams(params ...QueryParam) *Request {
+	r.queryParams = append(r.queryParams, params...)
+	return r
+}
+
 // Description will be added to new line each called.
 func (r *Request) Description(desc string) *Request {
 	r.descriptions = append(r.descriptions, desc)
@@ -81,10 +105,27 @@ func (r *Request) Required(required bool) *Request {
 	return r
 }
 
-func (r *Request) Components(gen *openapi3gen.Generator, requestName string) (components openapi3.Components, err error) {
+// Extension adds vendor extension to the request body, i.e: x-codegen-request-body-name.
+func (r *Request) Extension(key string, value interface{}) *Request {
+	if r.extensions == nil {
+		r.extensions = make(map[string]interface{})
+	}
 
+	r.extensions[key] = value
+	return r
+}
+
+func (r *Request) Components(gen *openapi3gen.Generator, requestName string, opts ...func(*utils.ComponentsOpt)) (components openapi3.Components, err error) {
+
+	componentsOpt := utils.NewComponentsOpt(opts...)
 	components = openapi3.NewComponents()
 
+	err = utils.ValidateExtensions(r.extensions)
+	if err != nil {
+		err = fmt.Errorf("invalid request body extension: %w", err)
+		return
+	}
+
 	for _, h := range r.headers {
 		if h == nil {
 			continue
@@ -98,7 +139,11 @@ func (r *Request) Components(gen *openapi3gen.Generator, requestName string) (co
 		}
 
 		// merge schema from headers (schemas, params, and headers) to output components
-		utils.MergeComponents(&components, headersComponents)
+		_, err = utils.NewMerger(utils.ConflictFail).Merge(&components, headersComponents, "")
+		if err != nil {
+			err = fmt.Errorf("merge request header components error: %w", err)
+			return
+		}
 	}
 
 	// generate path parameters
@@ -108,31 +153,51 @@ func (r *Request) Components(gen *openapi3gen.Generator, requestName string) (co
 	for _, param := range r.pathParams {
 		paramName := fmt.Sprintf("pathParam.%s.%s", requestName, param.Name)
 
-		// params is only simple value, and must not contain array or object
-		paramType := "string"
-		switch reflect.TypeOf(param.Value).Kind() {
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-			paramType = "integer"
+		err = utils.ValidateExtensions(param.Extensions)
+		if err != nil {
+			err = fmt.Errorf("invalid path param %s extension: %w", param.Name, err)
+			return
+		}
+
+		openapi3params[paramName] = &openapi3.ParameterRef{
+			Value: &openapi3.Parameter{
+				ExtensionProps: openapi3.ExtensionProps{Extensions: param.Extensions},
+				In:             "path",
+				Name:           param.Name,
+				Description:    param.Description,
+				Example:        param.Value,
+				Required:       true, // always true for in=path
+				Style:          "simple",
+				Schema: &openapi3.SchemaRef{
+					Value: &openapi3.Schema{
+						Type: paramType(param.Value),
+					},
+				},
+			},
+		}
+	}
 
-		case reflect.Float32, reflect.Float64:
-			paramType = "number"
+	// generate query parameters, the same way as path parameters
+	for _, param := range r.queryParams {
+		paramName := fmt.Sprintf("queryParam.%s.%s", requestName, param.Name)
 
-		case reflect.Bool:
-			paramType = "boolean"
+		err = utils.ValidateExtensions(param.Extensions)
+		if err != nil {
+			err = fmt.Errorf("invalid query param %s extension: %w", param.Name, err)
+			return
 		}
 
 		openapi3params[paramName] = &openapi3.ParameterRef{
 			Value: &openapi3.Parameter{
-				In:          "path",
-				Name:        param.Name,
-				Description: param.Description,
-				Example:     param.Value,
-				Required:    true, // always true for in=path
-				Style:       "simple",
+				ExtensionProps: openapi3.ExtensionProps{Extensions: param.Extensions},
+				In:             "query",
+				Name:           param.Name,
+				Description:    param.Description,
+				Example:        param.Value,
+				Required:       param.Required,
 				Schema: &openapi3.SchemaRef{
 					Value: &openapi3.Schema{
-						Type: paramType,
+						Type: paramType(param.Value),
 					},
 				},
 			},
@@ -140,18 +205,20 @@ func (r *Request) Components(gen *openapi3gen.Generator, requestName string) (co
 	}
 
 	openapi3schema := make(map[string]*openapi3.SchemaRef)
+	openapi3examples := make(map[string]*openapi3.ExampleRef)
 
 	// Define openapi3bodyRef here to ensure that this Request support multiple content type with different payload.
 	// If openapi3bodyRef is first generated, then it will have Value.Content = nil
 	openapi3bodyRef := &openapi3.RequestBodyRef{}
 	openapi3bodyRef.Value = &openapi3.RequestBody{}
 	openapi3bodyRef.Value.Required = r.required
+	openapi3bodyRef.Value.ExtensionProps.Extensions = r.extensions
 	openapi3bodyRef.Value.Description = strings.Join(r.descriptions, "\n\n")
 	openapi3bodyRef.Value.Content = make(map[string]*openapi3.MediaType)
 
 	for contentType, bodyPayload := range r.bodies {
 		// generate schemaRef and add to the schema map
-		schemaName := fmt.Sprintf("%T", bodyPayload.data)
+		schemaName := componentsOpt.SchemaNamer.SchemaName(reflect.TypeOf(bodyPayload.data))
 		if bodyPayload.opts.withSchemaName != "" {
 			schemaName = bodyPayload.opts.withSchemaName
 		}
@@ -163,7 +230,7 @@ func (r *Request) Components(gen *openapi3gen.Generator, requestName string) (co
 		var schemaRef *openapi3.SchemaRef
 		schemaRef, err = gen.NewSchemaRefForValue(bodyPayload.data, nil)
 		if err != nil {
-			err = fmt.Errorf("error generate schema request %s: %w", schemaName, err)
+			err = fmt.Errorf("error generate schema request %s: %w", schemaName, utils.NewSchemaError(contentType, bodyPayload.data, err))
 			return
 		}
 
@@ -172,12 +239,34 @@ func (r *Request) Components(gen *openapi3gen.Generator, requestName string) (co
 		// refer the created schema to openapi3RequestBodies
 		// if schema openapi3RequestBodies for the same contentType is already exist then replace it,
 		// if contentType is different, then add it.
-		openapi3bodyRef.Value.Content[contentType] = &openapi3.MediaType{
+		mediaType := &openapi3.MediaType{
 			Schema: &openapi3.SchemaRef{
 				Ref: fmt.Sprintf("#/components/schemas/%s", schemaName),
 			},
 		}
 
+		// add each named example to the components examples and refer it from this content type.
+		// Example name is prefixed with request name and content type, so the same name can be used in other content type.
+		for _, example := range bodyPayload.opts.examples {
+			exampleName := utils.SanitizeComponentName(fmt.Sprintf("%s-%s-%s", requestName, contentType, example.Name))
+			openapi3examples[exampleName] = &openapi3.ExampleRef{
+				Value: &openapi3.Example{
+					Summary:     example.Summary,
+					Description: example.Description,
+					Value:       example.Value,
+				},
+			}
+
+			if mediaType.Examples == nil {
+				mediaType.Examples = make(map[string]*openapi3.ExampleRef)
+			}
+
+			mediaType.Examples[example.Name] = &openapi3.ExampleRef{
+				Ref: fmt.Sprintf("#/components/examples/%s", exampleName),
+			}
+		}
+
+		openapi3bodyRef.Value.Content[contentType] = mediaType
 	}
 
 	// this Request only returns one map value *openapi3.RequestBodyRef.
@@ -191,8 +280,36 @@ func (r *Request) Components(gen *openapi3gen.Generator, requestName string) (co
 		Schemas:       openapi3schema,
 		RequestBodies: openapi3RequestBodies,
 		Parameters:    openapi3params,
+		Examples:      openapi3examples,
+	}
+
+	_, err = utils.NewMerger(utils.ConflictFail).Merge(&components, reqComponents, "")
+	if err != nil {
+		err = fmt.Errorf("merge request components error: %w", err)
 	}
 
-	utils.MergeComponents(&components, reqComponents)
 	return
 }
+
+// paramType returns openapi3 schema type of the parameter value.
+// Parameter is only simple value, and must not contain array or object.
+func paramType(value interface{}) string {
+	if value == nil {
+		return "string"
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+
+	case reflect.Float32, reflect.Float64:
+		return "number"
+
+	case reflect.Bool:
+		return "boolean"
+
+	default:
+		return "string"
+	}
+}
@@ -2,7 +2,16 @@ package request
 
 import "strings"
 
+// Example is a named example of the body payload, i.e: "minimal", "full" or "validation error".
+type Example struct {
+	Name        string
+	Summary     string
+	Description string
+	Value       interface{}
+}
+
 type bodyOpt struct {
+	examples              []Example
 	withPrefixRequestName bool
 	withSchemaName        string
 }
@@ -39,3 +48,12 @@ func UniquePerRequest() func(*bodyOpt) {
 		o.withPrefixRequestName = true
 	}
 }
+
+// WithExamples adds named examples to the body payload.
+// Each example is added to the components examples and referenced from the content type.
+// Example with the same name will replace the previous one.
+func WithExamples(examples ...Example) func(*bodyOpt) {
+	return func(o *bodyOpt) {
+		o.examples = append(o.examples, examples...)
+	}
+}
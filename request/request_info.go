@@ -0,0 +1,56 @@
+package request
+
+import (
+	"github.com/yusufsyaifudin/openapidoc/header"
+	"strings"
+)
+
+// Info is the read-only view of the Request.
+// It is used by the tools which need the original Go values, such as client generator or mock server.
+type Info struct {
+	// Bodies map k = content type, v = the Go value passed to Body
+	Bodies map[string]interface{}
+
+	// Examples map k = content type, v = named examples passed using WithExamples
+	Examples map[string][]Example
+
+	// Headers map k = header key, v = header value
+	Headers map[string]header.Map
+
+	PathParams  []PathParam
+	QueryParams []QueryParam
+	Description string
+	Required    bool
+}
+
+// Info returns the read-only view of the Request.
+func (r *Request) Info() Info {
+	info := Info{
+		Bodies:      make(map[string]interface{}),
+		Examples:    make(map[string][]Example),
+		Headers:     make(map[string]header.Map),
+		PathParams:  append([]PathParam{}, r.pathParams...),
+		QueryParams: append([]QueryParam{}, r.queryParams...),
+		Description: strings.Join(r.descriptions, "\n\n"),
+		Required:    r.required,
+	}
+
+	for contentType, bodyPayload := range r.bodies {
+		info.Bodies[contentType] = bodyPayload.data
+		if len(bodyPayload.opts.examples) > 0 {
+			info.Examples[contentType] = append([]Example{}, bodyPayload.opts.examples...)
+		}
+	}
+
+	for _, h := range r.headers {
+		if h == nil {
+			continue
+		}
+
+		for key, value := range h.Values() {
+			info.Headers[key] = value
+		}
+	}
+
+	return info
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/getkin/kin-openapi/openapi3gen"
 	"github.com/yusufsyaifudin/openapidoc/header"
 	"github.com/yusufsyaifudin/openapidoc/utils"
+	"reflect"
 	"strings"
 )
 
@@ -27,6 +28,12 @@ type Response struct {
 
 	// descriptions response description
 	descriptions []string
+
+	// links map k = link name, v = target operation and its parameters
+	links map[string]link
+
+	// extensions vendor extensions of the response
+	extensions map[string]interface{}
 }
 
 // NewResponse only return one openapi3respRef openapi3.ResponseRef.
@@ -36,6 +43,7 @@ func NewResponse() *Response {
 		bodies:       map[string]body{},
 		headers:      make([]*header.Header, 0),
 		descriptions: make([]string, 0),
+		links:        map[string]link{},
 	}
 }
 
@@ -74,14 +82,47 @@ func (r *Response) Description(desc string) *Response {
 	return r
 }
 
+// Extension adds vendor extension to the response, i.e: x-cache-ttl.
+func (r *Response) Extension(key string, value interface{}) *Response {
+	if r.extensions == nil {
+		r.extensions = make(map[string]interface{})
+	}
+
+	r.extensions[key] = value
+	return r
+}
+
+// Link tells the client which operation can be called next using values from this response.
+// params map k = parameter name in the target operation, v = constant or runtime expression,
+// for example: "id": "$response.body#/data/pet/id".
+// Target operation and its parameters are validated when the document is generated.
+func (r *Response) Link(name, targetOperationID string, params map[string]string) *Response {
+	r.links[name] = link{
+		operationID: targetOperationID,
+		params:      params,
+	}
+	return r
+}
+
 // Components returns openapi3.Components with the value of following fields:
 // * openapi3.Schemas
 // * openapi3.Headers
 // * openapi3.Responses
-func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode string) (components openapi3.Components, err error) {
+// * openapi3.Links
+// * openapi3.Examples
+//
+// opts sets how the components is generated, i.e: utils.WithSchemaNamer.
+func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode string, opts ...func(*utils.ComponentsOpt)) (components openapi3.Components, err error) {
 
+	componentsOpt := utils.NewComponentsOpt(opts...)
 	components = openapi3.NewComponents()
 
+	err = utils.ValidateExtensions(r.extensions)
+	if err != nil {
+		err = fmt.Errorf("invalid response extension: %w", err)
+		return
+	}
+
 	// foreach added header key, generate it and save generated headerRef to this map
 	// allHeaderRef contains KeyHeader:KeyHeaderRef
 	// i.e: Signature:#/components/headers/Signature
@@ -99,7 +140,11 @@ func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode
 		}
 
 		// merge schema from headers (schemas, params, and headers) to output components
-		utils.MergeComponents(&components, headersComponents)
+		_, err = utils.NewMerger(utils.ConflictFail).Merge(&components, headersComponents, "")
+		if err != nil {
+			err = fmt.Errorf("merge response header components error: %w", err)
+			return
+		}
 
 		for headerKey, headerRef := range h.RespHeaderRef() {
 			allHeaderRef[headerKey] = headerRef
@@ -108,8 +153,10 @@ func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode
 	}
 
 	openapi3schema := make(map[string]*openapi3.SchemaRef)
+	openapi3examples := make(map[string]*openapi3.ExampleRef)
 	openapi3respRef := &openapi3.ResponseRef{}
 	openapi3respRef.Value = &openapi3.Response{}
+	openapi3respRef.Value.ExtensionProps.Extensions = r.extensions
 
 	// responses should have required property 'description'
 	desc := strings.Join(r.descriptions, "\n\n")
@@ -124,9 +171,35 @@ func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode
 		}
 	}
 
+	// links is shared between content type too, the same as headers.
+	// Link name is prefixed with response name and http code, so the same link name can be used in other responses.
+	openapi3links := make(map[string]*openapi3.LinkRef)
+	openapi3respRef.Value.Links = map[string]*openapi3.LinkRef{}
+	for linkName, linkTarget := range r.links {
+		linkParams := make(map[string]interface{})
+		for paramName, paramValue := range linkTarget.params {
+			linkParams[paramName] = paramValue
+		}
+
+		linkRefName := fmt.Sprintf("%s-%s-%s", responseName, httpCode, linkName)
+		openapi3links[linkRefName] = &openapi3.LinkRef{
+			Value: &openapi3.Link{
+				OperationID: linkTarget.operationID,
+				Parameters:  linkParams,
+			},
+		}
+
+		openapi3respRef.Value.Links[linkName] = &openapi3.LinkRef{
+			Ref: fmt.Sprintf("#/components/links/%s", linkRefName),
+		}
+	}
+
 	// same response can have multiple response schema for different content type
 	openapi3respRef.Value.Content = make(map[string]*openapi3.MediaType)
 
+	// responseName is changed on each content type below, keep the original one as examples name prefix
+	examplePrefix := responseName
+
 	// only one response can be generated.
 	// We design that 1 Response is represents only to specific method and path.
 	// If user want to add multiple response with different method or path, they must call NewResponse() multiple times.
@@ -135,7 +208,7 @@ func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode
 	for contentType, bodyPayload := range r.bodies {
 		// generate schemaRef and add to the openapi3schema map
 		// if bodyPayload is come from the same struct that we defined in some places, then it will use the same schema name
-		schemaName := fmt.Sprintf("%T", bodyPayload.data)
+		schemaName := componentsOpt.SchemaNamer.SchemaName(reflect.TypeOf(bodyPayload.data))
 		if bodyPayload.opts.withSchemaName != "" {
 			schemaName = bodyPayload.opts.withSchemaName
 		}
@@ -143,7 +216,7 @@ func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode
 		var schemaRef *openapi3.SchemaRef
 		schemaRef, err = gen.NewSchemaRefForValue(bodyPayload.data, nil)
 		if err != nil {
-			err = fmt.Errorf("error generate openapi3schema response %s: %w", schemaName, err)
+			err = fmt.Errorf("error generate openapi3schema response %s: %w", schemaName, utils.NewSchemaError(contentType, bodyPayload.data, err))
 			return
 		}
 
@@ -151,12 +224,36 @@ func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode
 		openapi3schema[schemaName] = schemaRef
 
 		// refer the created openapi3schema to responses.
-		openapi3respRef.Value.Content[contentType] = &openapi3.MediaType{
+		mediaType := &openapi3.MediaType{
 			Schema: &openapi3.SchemaRef{
 				Ref: fmt.Sprintf("#/components/schemas/%s", schemaName),
 			},
 		}
 
+		// add each named example to the components examples and refer it from this content type.
+		// Example name is prefixed with response name, http code and content type,
+		// so the same name can be used in other responses.
+		for _, example := range bodyPayload.opts.examples {
+			exampleName := utils.SanitizeComponentName(fmt.Sprintf("%s-%s-%s-%s", examplePrefix, httpCode, contentType, example.Name))
+			openapi3examples[exampleName] = &openapi3.ExampleRef{
+				Value: &openapi3.Example{
+					Summary:     example.Summary,
+					Description: example.Description,
+					Value:       example.Value,
+				},
+			}
+
+			if mediaType.Examples == nil {
+				mediaType.Examples = make(map[string]*openapi3.ExampleRef)
+			}
+
+			mediaType.Examples[example.Name] = &openapi3.ExampleRef{
+				Ref: fmt.Sprintf("#/components/examples/%s", exampleName),
+			}
+		}
+
+		openapi3respRef.Value.Content[contentType] = mediaType
+
 		// responses can have the same content type (i.e: application/json), but different payload
 		// i.e: http 200 OK, can have different payload depending on request body.
 		// But, please note that if we already define the content type with specific http code, the values will be overrided.
@@ -193,10 +290,15 @@ func (r *Response) Components(gen *openapi3gen.Generator, responseName, httpCode
 	respComponents := openapi3.Components{
 		Schemas:   openapi3schema,
 		Responses: openapi3responseBodies,
+		Links:     openapi3links,
+		Examples:  openapi3examples,
 	}
 
-	// merge response components (schema, responses) to output components
-	utils.MergeComponents(&components, respComponents)
+	// merge response components (schema, responses, links, examples) to output components
+	_, err = utils.NewMerger(utils.ConflictFail).Merge(&components, respComponents, "")
+	if err != nil {
+		err = fmt.Errorf("merge response components error: %w", err)
+	}
 
 	return
 }
@@ -2,10 +2,24 @@ package response
 
 import "strings"
 
+// Example is a named example of the body payload, i.e: "minimal", "full" or "validation error".
+type Example struct {
+	Name        string
+	Summary     string
+	Description string
+	Value       interface{}
+}
+
 type bodyOpt struct {
+	examples       []Example
 	withSchemaName string
 }
 
+type link struct {
+	operationID string
+	params      map[string]string
+}
+
 type body struct {
 	data interface{}
 	opts *bodyOpt
@@ -19,3 +33,12 @@ func WithSchemaName(name string) func(*bodyOpt) {
 		o.withSchemaName = strings.ReplaceAll(strings.TrimSpace(name), "\n", "")
 	}
 }
+
+// WithExamples adds named examples to the body payload.
+// Each example is added to the components examples and referenced from the content type.
+// Example with the same name will replace the previous one.
+func WithExamples(examples ...Example) func(*bodyOpt) {
+	return func(o *bodyOpt) {
+		o.examples = append(o.examples, examples...)
+	}
+}
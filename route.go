@@ -0,0 +1,168 @@
+package openapidoc
+
+import (
+	"fmt"
+	"github.com/yusufsyaifudin/openapidoc/request"
+	"github.com/yusufsyaifudin/openapidoc/response"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"sort"
+	"strings"
+)
+
+// DuplicateRoutePolicy is what Add does when the same method and path is already added.
+type DuplicateRoutePolicy int
+
+const (
+	// DuplicateRouteError returns AddError wrapping ErrDuplicateRoute.
+	DuplicateRouteError DuplicateRoutePolicy = iota
+
+	// DuplicateRouteReplace replaces the existing route, as if Replace is called.
+	DuplicateRouteReplace
+
+	// DuplicateRouteMerge uses the new request, merges the responses (the new one wins on the same status),
+	// and keeps the existing operation options which are not set again, i.e: operationId.
+	DuplicateRouteMerge
+)
+
+// Route is the added method and path with its original request and responses.
+type Route struct {
+	Method      string
+	Path        string
+	OperationID string
+	Tags        []string
+	Audiences   []string
+	Request     *request.Request
+
+	// Responses map k = http status code in lower case, i.e: 200 or 2xx, v = the response
+	Responses map[string]*response.Response
+
+	// Statuses is the sorted keys of Responses
+	Statuses []string
+
+	// RequestTypes map k = content type, v = Go type of the request body, i.e: main.Pet
+	RequestTypes map[string]string
+
+	// ResponseTypes map k = http status code in lower case, v = map k = content type, v = Go type of the response body
+	ResponseTypes map[string]map[string]string
+
+	// operation is the options passed to Add, used to merge the duplicate route
+	operation OperationConfig
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+// Routes returns all added routes sorted by path and method.
+func (r *Registry) Routes() []Route {
+	routes := make([]Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		routes = append(routes, route)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// Lookup returns the added route of the method and path.
+func (r *Registry) Lookup(method, path string) (Route, bool) {
+	route, exist := r.routes[routeKey(method, path)]
+	return route, exist
+}
+
+// Remove removes the added method and path, and the components which are not referenced anymore by other routes.
+// The error of Add of the method and path is removed too, so it is not returned by Generate anymore.
+// It returns false if the method and path is not added and has no error.
+func (r *Registry) Remove(method, path string) bool {
+	cleared := r.clearErrors(method, path)
+	return r.remove(method, path) || cleared
+}
+
+// remove removes the added method and path without clearing its error, see Remove.
+func (r *Registry) remove(method, path string) bool {
+	key := routeKey(method, path)
+	if _, exist := r.routes[key]; !exist {
+		return false
+	}
+
+	delete(r.routes, key)
+
+	pathItem := r.paths[path]
+	if pathItem == nil {
+		return true
+	}
+
+	operation := pathItem.Operations()[method]
+	if operation == nil {
+		return true
+	}
+
+	removedRefs := utils.CollectRefs(r.components, operation)
+	pathItem.SetOperation(method, nil)
+	if len(pathItem.Operations()) <= 0 {
+		delete(r.paths, path)
+	}
+
+	remaining := make([]interface{}, 0, len(r.paths))
+	for _, p := range r.paths {
+		remaining = append(remaining, p)
+	}
+
+	usedRefs := utils.CollectRefs(r.components, remaining...)
+	for ref := range removedRefs {
+		if _, used := usedRefs[ref]; !used {
+			utils.DeleteComponent(r.components, ref)
+			r.merger.Forget(ref)
+		}
+	}
+
+	return true
+}
+
+// newRoute copy the responses map, so the later changes on the map passed to Add is not affected.
+func newRoute(method, path string, operation OperationConfig, req *request.Request, resp map[string]*response.Response) Route {
+	route := Route{
+		Method:        method,
+		Path:          path,
+		OperationID:   operation.operationID,
+		Tags:          append([]string{}, operation.tags...),
+		Audiences:     append([]string{}, operation.audiences...),
+		Request:       req,
+		Responses:     make(map[string]*response.Response, len(resp)),
+		Statuses:      make([]string, 0, len(resp)),
+		RequestTypes:  make(map[string]string),
+		ResponseTypes: make(map[string]map[string]string),
+		operation:     *operation.clone(),
+	}
+
+	if req != nil {
+		for contentType, body := range req.Info().Bodies {
+			route.RequestTypes[contentType] = fmt.Sprintf("%T", body)
+		}
+	}
+
+	for httpCode, respInstance := range resp {
+		if respInstance == nil {
+			continue
+		}
+
+		httpCode = strings.ToLower(httpCode)
+		route.Responses[httpCode] = respInstance
+		route.Statuses = append(route.Statuses, httpCode)
+
+		route.ResponseTypes[httpCode] = make(map[string]string)
+		for contentType, body := range respInstance.Info().Bodies {
+			route.ResponseTypes[httpCode][contentType] = fmt.Sprintf("%T", body)
+		}
+	}
+
+	sort.Strings(route.Statuses)
+	return route
+}
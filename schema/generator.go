@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/yusufsyaifudin/openapidoc/utils"
 	"io"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 )
@@ -18,6 +20,22 @@ type Generator struct {
 	logWriter    io.Writer
 	goTag        []string
 	schemaPrefix string
+	schemaNamer  utils.SchemaNamer
+
+	// validatorTags maps the validate tag to the schema constraints
+	validatorTags bool
+
+	// strictTags returns error on unknown keyword of the openapi3 tag
+	strictTags bool
+
+	// requiredPolicy decides the required properties of the struct schema
+	requiredPolicy utils.RequiredPolicy
+
+	// constEnums writes the typed constants as enum, nil if disabled
+	constEnums *utils.ConstEnums
+
+	// typeSchemas is the schema used instead of the generated one, see utils.TypeSchemas
+	typeSchemas utils.TypeSchemas
 }
 
 // WithLog enables debug log to see how Schema will be appended as final Schemas.
@@ -64,12 +82,74 @@ func WithSchemaPrefix(prefix string) Opt {
 	}
 }
 
+// WithSchemaNamer sets how the struct schema is named, by default utils.NewSchemaNamer.
+func WithSchemaNamer(namer utils.SchemaNamer) Opt {
+	return func(gen *Generator) error {
+		if namer == nil {
+			return fmt.Errorf("nil schema namer")
+		}
+
+		gen.schemaNamer = namer
+		return nil
+	}
+}
+
+// WithValidatorTags maps the go-playground/validator rules in the validate tag to the schema constraints,
+// i.e: `validate:"required,min=1,max=50,email"`, see utils.ApplyValidatorTag.
+func WithValidatorTags() Opt {
+	return func(gen *Generator) error {
+		gen.validatorTags = true
+		return nil
+	}
+}
+
+// WithStrictTags returns error when the openapi3 tag has unknown keyword, i.e: `openapi3:"descr:Pet name"`.
+func WithStrictTags() Opt {
+	return func(gen *Generator) error {
+		gen.strictTags = true
+		return nil
+	}
+}
+
+// WithRequiredPolicy sets how the required properties of the struct schema is decided, by default utils.RequiredExplicit.
+// The field with `openapi3:"required"` or `openapi3:"optional"` always overrides the policy.
+func WithRequiredPolicy(policy utils.RequiredPolicy) Opt {
+	return func(gen *Generator) error {
+		gen.requiredPolicy = policy
+		return nil
+	}
+}
+
+// WithConstEnums writes the typed constants of the named type as its enum, see utils.ConstEnums,
+// i.e: const ( KindCat PetKind = "cat"; KindDog PetKind = "dog" ) is the enum of PetKind.
+func WithConstEnums() Opt {
+	return func(gen *Generator) error {
+		gen.constEnums = utils.NewConstEnums()
+		return nil
+	}
+}
+
+// WithTypeSchema uses the schema for the type of value instead of the generated one,
+// i.e: WithTypeSchema(resource.Quantity{}, openapi3.NewStringSchema()) for the type which cannot implement utils.SchemaProvider.
+func WithTypeSchema(value interface{}, schema *openapi3.Schema) Opt {
+	return func(gen *Generator) error {
+		if value == nil || schema == nil {
+			return fmt.Errorf("nil value or schema of type schema")
+		}
+
+		gen.typeSchemas.Register(value, schema)
+		return nil
+	}
+}
+
 func NewGenerator(options ...Opt) (*Generator, error) {
 
 	gen := &Generator{
-		logEnabled: false,
-		logWriter:  &noopWriter{},
-		goTag:      []string{"json"},
+		logEnabled:  false,
+		logWriter:   &noopWriter{},
+		goTag:       []string{"json"},
+		schemaNamer: utils.NewSchemaNamer(),
+		typeSchemas: make(utils.TypeSchemas),
 	}
 
 	for _, option := range options {
@@ -84,18 +164,17 @@ func NewGenerator(options ...Opt) (*Generator, error) {
 	return gen, nil
 }
 
-func getSchemaName(prefix string, structValue interface{}) string {
-	schemaName := fmt.Sprintf("%T", structValue)
+// getSchemaName returns the schema name of the value using the SchemaNamer,
+// except for string which is used as is, i.e: the key of the map.
+func (g *Generator) getSchemaName(structValue interface{}) string {
+	var schemaName string
 	if reflect.TypeOf(structValue).Kind() == reflect.String {
 		schemaName = fmt.Sprintf("%s", structValue)
+	} else {
+		schemaName = g.schemaNamer.SchemaName(reflect.TypeOf(structValue))
 	}
 
-	schemaName = strings.TrimPrefix(schemaName, "*")
-	for strings.HasPrefix(schemaName, "*") {
-		schemaName = strings.TrimPrefix(schemaName, "*")
-	}
-
-	return fmt.Sprintf("%s%s", prefix, schemaName)
+	return fmt.Sprintf("%s%s", g.schemaPrefix, schemaName)
 }
 
 type GenerateOut struct {
@@ -105,7 +184,7 @@ type GenerateOut struct {
 }
 
 func (g *Generator) Generate(ctx context.Context, structValue interface{}) (out GenerateOut, err error) {
-	parentSchemaName := getSchemaName(g.schemaPrefix, structValue)
+	parentSchemaName := g.getSchemaName(structValue)
 
 	schemaRef := make(map[string]*openapi3.SchemaRef)
 	err = g.generate(ctx, 0, "", structValue, schemaRef)
@@ -113,9 +192,17 @@ func (g *Generator) Generate(ctx context.Context, structValue interface{}) (out
 		return
 	}
 
+	// all values in the struct are used as example, so the struct itself is the example of the parent schema
+	examples := openapi3.Examples{
+		parentSchemaName: &openapi3.ExampleRef{
+			Value: openapi3.NewExample(structValue),
+		},
+	}
+
 	out = GenerateOut{
 		ParentSchemaName: parentSchemaName,
 		Schemas:          schemaRef,
+		Examples:         examples,
 	}
 	return
 }
@@ -129,7 +216,7 @@ func (g *Generator) generate(
 ) (err error) {
 	currentSchema := map[string]*openapi3.SchemaRef{}
 
-	schemaName := getSchemaName(g.schemaPrefix, structValue)
+	schemaName := g.getSchemaName(structValue)
 
 	fields := reflect.TypeOf(structValue)
 	values := reflect.ValueOf(structValue)
@@ -143,6 +230,16 @@ func (g *Generator) generate(
 		values = values.Elem()
 	}
 
+	// the schema of utils.SchemaProvider or WithTypeSchema is used as is, like json.Marshaler overrides the encoding
+	if provided, ok := g.typeSchemas.Schema(fields); ok {
+		if provided.Example == nil && values.IsValid() && values.CanInterface() {
+			provided.Example = values.Interface()
+		}
+
+		schemaRef[schemaName] = &openapi3.SchemaRef{Value: provided}
+		return
+	}
+
 	if values.Kind() != reflect.Struct || fields.Kind() != reflect.Struct {
 		if g.logEnabled {
 			msg := make([]byte, 0)
@@ -181,6 +278,8 @@ func (g *Generator) generate(
 
 	}
 
+	// fieldTags map k = property name, v = the struct field, used to apply the openapi3 and validate tag
+	fieldTags := make(map[string]reflect.StructField)
 	for i := 0; i < numField; i++ {
 
 		field := fields.Field(i)
@@ -208,6 +307,8 @@ func (g *Generator) generate(
 			propertyFieldName = field.Name
 		}
 
+		fieldTags[propertyFieldName] = field
+
 		if g.logEnabled {
 			msg := make([]byte, 0)
 			msg = fmt.Appendf(msg,
@@ -228,6 +329,20 @@ func (g *Generator) generate(
 			continue
 		}
 
+		if provided, ok := g.typeSchemas.Schema(field.Type); ok {
+			// the nil pointer has no example
+			for value.Kind() == reflect.Ptr && !value.IsNil() {
+				value = value.Elem()
+			}
+
+			if provided.Example == nil && value.Kind() != reflect.Ptr {
+				provided.Example = value.Interface()
+			}
+
+			currentSchema[propertyFieldName] = &openapi3.SchemaRef{Value: provided}
+			continue
+		}
+
 		// iterate over the values
 		// With this methodology, we expect that all values must be set in the struct when we add as open api schema.
 		// All values in this struct also be used as example.
@@ -297,7 +412,7 @@ func (g *Generator) generate(
 
 			// This is enough if we have simple object.
 			// But, if we have multiple object or recursive object, then we need allOf method
-			newSchemaName := getSchemaName(g.schemaPrefix, value.Interface())
+			newSchemaName := g.getSchemaName(value.Interface())
 			currentSchema[propertyFieldName] = &openapi3.SchemaRef{
 				Ref: fmt.Sprintf("#/components/schemas/%s", newSchemaName),
 			}
@@ -398,7 +513,7 @@ func (g *Generator) generate(
 				return
 			}
 
-			newSchemaName := getSchemaName(g.schemaPrefix, ptrVal.Interface())
+			newSchemaName := g.getSchemaName(ptrVal.Interface())
 			currentSchema[propertyFieldName] = &openapi3.SchemaRef{
 				Ref: fmt.Sprintf("#/components/schemas/%s", newSchemaName),
 			}
@@ -418,11 +533,18 @@ func (g *Generator) generate(
 				if mapValue.Kind() == reflect.String {
 					mapExample[mapKeyName] = mapValue.Interface()
 
+					mapValueSchema := &openapi3.Schema{
+						Type:    "string",
+						Example: fmt.Sprintf("%s", mapValue.Interface()),
+					}
+
+					if err = g.applyEnum(mapValue.Type(), mapValueSchema); err != nil {
+						err = &utils.FieldError{Name: propertyFieldName, Type: field.Type, Err: err}
+						return
+					}
+
 					mapSchemaProps[mapKeyName] = &openapi3.SchemaRef{
-						Value: &openapi3.Schema{
-							Type:    "string",
-							Example: fmt.Sprintf("%s", mapValue.Interface()),
-						},
+						Value: mapValueSchema,
 					}
 					continue
 				}
@@ -445,7 +567,7 @@ func (g *Generator) generate(
 			mapSchemaPropsRef := make(map[string]*openapi3.SchemaRef)
 			for mapSchemaName, mapSchemaRef := range mapSchemaProps {
 				// add to final output schema map
-				mapSchemaName = getSchemaName(g.schemaPrefix, mapSchemaName)
+				mapSchemaName = g.getSchemaName(mapSchemaName)
 				schemaRef[mapSchemaName] = mapSchemaRef
 
 				mapSchemaPropsRef[mapSchemaName] = &openapi3.SchemaRef{
@@ -490,12 +612,86 @@ func (g *Generator) generate(
 		_, _ = g.logWriter.Write(msg)
 	}
 
+	required, err := g.applyFieldTags(currentSchema, fieldTags)
+	if err != nil {
+		err = fmt.Errorf("struct %s: %w", schemaName, err)
+		return
+	}
+
+	structSchema := &openapi3.Schema{
+		Type:       "object",
+		Properties: currentSchema,
+		Required:   required,
+	}
+
+	if err = utils.ValidateRequired("", fields, structSchema); err != nil {
+		err = fmt.Errorf("struct %s: %w", schemaName, err)
+		return
+	}
+
 	schemaRef[schemaName] = &openapi3.SchemaRef{
-		Value: &openapi3.Schema{
-			Type:       "object",
-			Properties: currentSchema,
-		},
+		Value: structSchema,
 	}
 
 	return
 }
+
+// applyEnum writes the enum of the type implementing utils.OpenAPIEnum, or its constants if enabled.
+func (g *Generator) applyEnum(t reflect.Type, schema *openapi3.Schema) error {
+	if g.constEnums != nil {
+		return g.constEnums.ApplyEnum(t, schema)
+	}
+
+	return utils.ApplyEnum(t, schema)
+}
+
+// applyFieldTags applies the enum of the field type, the openapi3 tag and the validate tag (if enabled) of each field to the property schema,
+// and returns the sorted required properties using the required policy and the validate tag.
+// The explicit `openapi3:"required"` or `openapi3:"optional"` overrides both.
+func (g *Generator) applyFieldTags(properties openapi3.Schemas, fields map[string]reflect.StructField) ([]string, error) {
+	required := make([]string, 0)
+	for name, property := range properties {
+		field, exist := fields[name]
+		if !exist || property == nil {
+			continue
+		}
+
+		if err := g.applyEnum(field.Type, property.Value); err != nil {
+			return nil, &utils.FieldError{Name: name, Type: field.Type, Err: err}
+		}
+
+		err := utils.ApplyOpenAPITag(name, field.Type, field.Tag.Get(utils.OpenAPITag), property.Value, g.strictTags)
+		if err != nil {
+			return nil, err
+		}
+
+		isRequired, explicit := utils.FieldRequired(field, g.requiredPolicy)
+		if g.validatorTags {
+			// the referenced schema is not changed, only the required rule is used
+			value := property.Value
+			if value == nil {
+				value = &openapi3.Schema{}
+			}
+
+			validatorRequired, err := utils.ApplyValidatorTag(field.Tag.Get("validate"), value)
+			if err != nil {
+				return nil, &utils.FieldError{Name: name, Type: field.Type, Err: err}
+			}
+
+			if !explicit {
+				isRequired = isRequired || validatorRequired
+			}
+		}
+
+		if isRequired {
+			required = append(required, name)
+		}
+	}
+
+	if len(required) <= 0 {
+		return nil, nil
+	}
+
+	sort.Strings(required)
+	return required, nil
+}
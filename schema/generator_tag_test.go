@@ -0,0 +1,252 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/yusufsyaifudin/openapidoc/schema/testasset"
+	"github.com/yusufsyaifudin/openapidoc/utils"
+	"testing"
+)
+
+func TestValidatorTags(t *testing.T) {
+	type Owner struct {
+		Name string `json:"name" validate:"required,max=20"`
+	}
+
+	type Pet struct {
+		Name  string   `json:"name" validate:"required,min=1"`
+		Kind  string   `json:"kind" validate:"oneof=cat dog"`
+		Age   int      `json:"age" validate:"gte=0,lte=30"`
+		Tags  []string `json:"tags" validate:"min=1"`
+		Owner *Owner   `json:"owner" validate:"required"`
+	}
+
+	v := Pet{
+		Name:  "kitty",
+		Kind:  "cat",
+		Age:   2,
+		Tags:  []string{"cute"},
+		Owner: &Owner{Name: "john"},
+	}
+
+	g, err := NewGenerator(WithValidatorTags())
+	assert.NoError(t, err)
+
+	out, err := g.Generate(context.Background(), v)
+	assert.NoError(t, err)
+
+	pet := out.Schemas[out.ParentSchemaName].Value
+	assert.Equal(t, []string{"name", "owner"}, pet.Required)
+	assert.Equal(t, uint64(1), pet.Properties["name"].Value.MinLength)
+	assert.Equal(t, []interface{}{"cat", "dog"}, pet.Properties["kind"].Value.Enum)
+	assert.Equal(t, float64(30), *pet.Properties["age"].Value.Max)
+	assert.Equal(t, uint64(1), pet.Properties["tags"].Value.MinItems)
+
+	owner := out.Schemas["schema.Owner"].Value
+	assert.Equal(t, []string{"name"}, owner.Required)
+	assert.Equal(t, uint64(20), *owner.Properties["name"].Value.MaxLength)
+
+	// validate tag is ignored by default
+	g, err = NewGenerator()
+	assert.NoError(t, err)
+
+	out, err = g.Generate(context.Background(), v)
+	assert.NoError(t, err)
+	assert.Empty(t, out.Schemas[out.ParentSchemaName].Value.Required)
+}
+
+func TestOpenAPITag(t *testing.T) {
+	type Pet struct {
+		Name  string   `json:"name" openapi3:"title:Name,minLength:1,maxLength:50,pattern:^[a-z]+$"`
+		Kind  string   `json:"kind" openapi3:"enum:cat;dog,default:cat"`
+		Age   int      `json:"age" openapi3:"min:0,max:30,exclusiveMax,enum:1;2;3"`
+		Tags  []string `json:"tags" openapi3:"minItems:1,uniqueItems,enum:cute;fluffy"`
+		Token string   `json:"token" openapi3:"readOnly,deprecated:true,nullable"`
+	}
+
+	g, err := NewGenerator()
+	assert.NoError(t, err)
+
+	out, err := g.Generate(context.Background(), Pet{Name: "kitty", Kind: "cat", Tags: []string{"cute"}})
+	assert.NoError(t, err)
+
+	pet := out.Schemas[out.ParentSchemaName].Value
+	name := pet.Properties["name"].Value
+	assert.Equal(t, "Name", name.Title)
+	assert.Equal(t, uint64(1), name.MinLength)
+	assert.Equal(t, uint64(50), *name.MaxLength)
+	assert.Equal(t, "^[a-z]+$", name.Pattern)
+
+	kind := pet.Properties["kind"].Value
+	assert.Equal(t, []interface{}{"cat", "dog"}, kind.Enum)
+	assert.Equal(t, "cat", kind.Default)
+
+	age := pet.Properties["age"].Value
+	assert.Equal(t, float64(0), *age.Min)
+	assert.Equal(t, float64(30), *age.Max)
+	assert.True(t, age.ExclusiveMax)
+	assert.Equal(t, []interface{}{1, 2, 3}, age.Enum)
+
+	tags := pet.Properties["tags"].Value
+	assert.Equal(t, uint64(1), tags.MinItems)
+	assert.True(t, tags.UniqueItems)
+	assert.Nil(t, tags.Enum)
+	assert.Equal(t, []interface{}{"cute", "fluffy"}, tags.Items.Value.Enum)
+
+	token := pet.Properties["token"].Value
+	assert.True(t, token.ReadOnly)
+	assert.True(t, token.Deprecated)
+	assert.True(t, token.Nullable)
+}
+
+func TestStrictTags(t *testing.T) {
+	type Pet struct {
+		Name string `json:"name" openapi3:"descr:Pet name,x-order:1"`
+		Note string `json:"note" openapi3:"desc:'kind, age and sound'"`
+	}
+
+	g, err := NewGenerator()
+	assert.NoError(t, err)
+
+	out, err := g.Generate(context.Background(), Pet{})
+	assert.NoError(t, err)
+	assert.Equal(t, "kind, age and sound", out.Schemas[out.ParentSchemaName].Value.Properties["note"].Value.Description)
+
+	g, err = NewGenerator(WithStrictTags())
+	assert.NoError(t, err)
+
+	_, err = g.Generate(context.Background(), Pet{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown openapi3 tag keyword 'descr'")
+	}
+}
+
+func TestRequiredPolicy(t *testing.T) {
+	type Pet struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name,omitempty"`
+		Sound string `json:"sound" openapi3:"optional"`
+		Kind  string `json:"kind,omitempty" openapi3:"required"`
+	}
+
+	g, err := NewGenerator()
+	assert.NoError(t, err)
+
+	out, err := g.Generate(context.Background(), Pet{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kind"}, out.Schemas[out.ParentSchemaName].Value.Required)
+
+	g, err = NewGenerator(WithRequiredPolicy(utils.RequiredInferred))
+	assert.NoError(t, err)
+
+	out, err = g.Generate(context.Background(), Pet{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "kind"}, out.Schemas[out.ParentSchemaName].Value.Required)
+}
+
+type petKind string
+
+func (petKind) OpenAPIEnum() []interface{} {
+	return []interface{}{petKind("cat"), petKind("dog")}
+}
+
+func TestEnum(t *testing.T) {
+	type Listing struct {
+		Kind     petKind            `json:"kind"`
+		Accepted []petKind          `json:"accepted"`
+		Owners   map[string]petKind `json:"owners"`
+	}
+
+	g, err := NewGenerator()
+	assert.NoError(t, err)
+
+	out, err := g.Generate(context.Background(), Listing{
+		Kind:     "cat",
+		Accepted: []petKind{"dog"},
+		Owners:   map[string]petKind{"kitty": "cat"},
+	})
+	assert.NoError(t, err)
+
+	kinds := []interface{}{"cat", "dog"}
+	listing := out.Schemas[out.ParentSchemaName].Value
+	assert.Equal(t, kinds, listing.Properties["kind"].Value.Enum)
+	assert.Equal(t, kinds, listing.Properties["accepted"].Value.Items.Value.Enum)
+	assert.Equal(t, kinds, out.Schemas["kitty"].Value.Enum)
+}
+
+func TestConstEnums(t *testing.T) {
+	type Task struct {
+		Kind     testasset.PetKind    `json:"kind"`
+		Priority []testasset.Priority `json:"priority"`
+	}
+
+	task := Task{Kind: testasset.PetKindCat, Priority: []testasset.Priority{testasset.PriorityLow}}
+
+	g, err := NewGenerator()
+	assert.NoError(t, err)
+
+	out, err := g.Generate(context.Background(), task)
+	assert.NoError(t, err)
+	assert.Empty(t, out.Schemas[out.ParentSchemaName].Value.Properties["kind"].Value.Enum)
+
+	g, err = NewGenerator(WithConstEnums())
+	assert.NoError(t, err)
+
+	out, err = g.Generate(context.Background(), task)
+	assert.NoError(t, err)
+
+	kind := out.Schemas[out.ParentSchemaName].Value.Properties["kind"].Value
+	assert.Equal(t, []interface{}{"cat", "dog"}, kind.Enum)
+	assert.Equal(t, []interface{}{"PetKindCat", "PetKindDog"}, kind.Extensions[utils.EnumVarNamesExtension])
+	assert.Equal(t, []interface{}{"PetKindCat is the cat.", "PetKindDog is the dog."}, kind.Extensions[utils.EnumDescriptionsExtension])
+
+	priority := out.Schemas[out.ParentSchemaName].Value.Properties["priority"].Value.Items.Value
+	assert.Equal(t, []interface{}{float64(1), float64(2), float64(10)}, priority.Enum)
+	assert.Equal(t, []interface{}{"", "", "PriorityUrgent is the highest priority."}, priority.Extensions[utils.EnumDescriptionsExtension])
+}
+
+type money int64
+
+func (money) OpenAPISchema() *openapi3.Schema {
+	return &openapi3.Schema{Type: "string", Format: "decimal"}
+}
+
+func (m money) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%d.%02d"`, m/100, m%100)), nil
+}
+
+func TestTypeSchema(t *testing.T) {
+	type Order struct {
+		Total    money                `json:"total" openapi3:"desc:Total price"`
+		Discount *money               `json:"discount"`
+		CPU      testasset.Quantity   `json:"cpu"`
+		Items    []testasset.Quantity `json:"items"`
+	}
+
+	order := Order{Total: 1050, CPU: testasset.NewQuantity("100m"), Items: []testasset.Quantity{testasset.NewQuantity("1Gi")}}
+
+	_, err := NewGenerator(WithTypeSchema(nil, openapi3.NewStringSchema()))
+	assert.Error(t, err)
+
+	g, err := NewGenerator(WithTypeSchema(testasset.Quantity{}, &openapi3.Schema{Type: "string", Pattern: "^[0-9]+[a-zA-Z]*$"}))
+	assert.NoError(t, err)
+
+	out, err := g.Generate(context.Background(), order)
+	assert.NoError(t, err)
+
+	properties := out.Schemas[out.ParentSchemaName].Value.Properties
+	assert.Equal(t, "decimal", properties["total"].Value.Format)
+	assert.Equal(t, "Total price", properties["total"].Value.Description)
+	assert.Equal(t, money(1050), properties["total"].Value.Example)
+	assert.Equal(t, "decimal", properties["discount"].Value.Format)
+	assert.Nil(t, properties["discount"].Value.Example)
+	assert.Equal(t, "^[0-9]+[a-zA-Z]*$", properties["cpu"].Value.Pattern)
+
+	// the element of array is the component schema
+	itemRef := properties["items"].Value.Items.Value.AnyOf[0].Ref
+	quantity := out.Schemas[itemRef[len("#/components/schemas/"):]].Value
+	assert.Equal(t, "string", quantity.Type)
+	assert.Empty(t, quantity.Properties)
+}
@@ -0,0 +1,24 @@
+package testasset
+
+// PetKind has no method, its enum is read from the constants.
+type PetKind string
+
+const (
+	// PetKindCat is the cat.
+	PetKindCat PetKind = "cat"
+	PetKindDog PetKind = "dog" // PetKindDog is the dog.
+
+	// PetKindDefault is the same value as PetKindCat, so it is not written twice.
+	PetKindDefault = PetKindCat
+)
+
+// Priority has the iota constants.
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityHigh
+)
+
+// PriorityUrgent is the highest priority.
+const PriorityUrgent Priority = 10
@@ -1,56 +1,322 @@
 package utils
 
-import "github.com/getkin/kin-openapi/openapi3"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/hashicorp/go-multierror"
+	"reflect"
+	"sort"
+)
 
-// MergeComponents merge component from src to dst.
+// ConflictPolicy is what Merger does when the component with the same name has different value.
+type ConflictPolicy int
+
+const (
+	// ConflictFail returns *ConflictError.
+	ConflictFail ConflictPolicy = iota
+
+	// ConflictRename adds the new component using the unique name, i.e: model.User_2
+	// and all references to it in the merged components is changed.
+	ConflictRename
+
+	// ConflictOverwrite replaces the existing component with the new one.
+	ConflictOverwrite
+)
+
+// ConflictError is returned when two different components has the same name.
+type ConflictError struct {
+	// Ref is the reference of the conflicted component, i.e: #/components/schemas/model.User
+	Ref string
+
+	// Origin is where the new component comes from, ExistingOrigin is where the existing one comes from.
+	// i.e: POST /users request or GET /users/{id} response 200
+	Origin         string
+	ExistingOrigin string
+}
+
+func (e *ConflictError) Error() string {
+	origin, existingOrigin := e.Origin, e.ExistingOrigin
+	if origin == "" {
+		origin = "unknown origin"
+	}
+
+	if existingOrigin == "" {
+		existingOrigin = "unknown origin"
+	}
+
+	return fmt.Sprintf("component %s from %s is different from the existing one from %s", e.Ref, origin, existingOrigin)
+}
+
+// MergeComponents merge component from src to dst, the component with the same name is overwritten.
+// Use NewMerger to fail or rename on conflict.
 func MergeComponents(dst *openapi3.Components, src openapi3.Components) {
+	_, _ = NewMerger(ConflictOverwrite).Merge(dst, src, "")
+}
+
+// Merger merges the components using the conflict policy,
+// and remembers where each component comes from, so the conflict error can name both origins.
+type Merger struct {
+	policy ConflictPolicy
+
+	// origins map k = component reference, v = where the component comes from
+	origins map[string]string
+}
+
+func NewMerger(policy ConflictPolicy) *Merger {
+	return &Merger{
+		policy:  policy,
+		origins: make(map[string]string),
+	}
+}
+
+// Clone returns the copy of the Merger, i.e: to stage the merge and discard it on error.
+func (m *Merger) Clone() *Merger {
+	cloned := NewMerger(m.policy)
+	for ref, origin := range m.origins {
+		cloned.origins[ref] = origin
+	}
+
+	return cloned
+}
+
+// Forget removes the origin of the component, it must be called when the component is deleted.
+func (m *Merger) Forget(ref string) {
+	delete(m.origins, ref)
+}
+
+// Merge merges src to dst, origin is where the src comes from, i.e: POST /users request.
+// It returns the renamed references, map k = reference in src, v = the new reference in dst,
+// the caller must use the new reference to refer the renamed component.
+// On error, dst is not changed.
+func (m *Merger) Merge(dst *openapi3.Components, src openapi3.Components, origin string) (renames map[string]string, err error) {
+	renames = make(map[string]string)
 	if dst == nil {
 		return
 	}
 
-	// merge schema
-	if dst.Schemas == nil {
-		dst.Schemas = make(map[string]*openapi3.SchemaRef)
+	// find all conflicts first, so dst is not changed on error
+	var conflicts []string
+	conflicts = append(conflicts, conflictNames("schemas", dst.Schemas, src.Schemas)...)
+	conflicts = append(conflicts, conflictNames("parameters", dst.Parameters, src.Parameters)...)
+	conflicts = append(conflicts, conflictNames("headers", dst.Headers, src.Headers)...)
+	conflicts = append(conflicts, conflictNames("requestBodies", dst.RequestBodies, src.RequestBodies)...)
+	conflicts = append(conflicts, conflictNames("responses", dst.Responses, src.Responses)...)
+	conflicts = append(conflicts, conflictNames("securitySchemes", dst.SecuritySchemes, src.SecuritySchemes)...)
+	conflicts = append(conflicts, conflictNames("examples", dst.Examples, src.Examples)...)
+	conflicts = append(conflicts, conflictNames("links", dst.Links, src.Links)...)
+	conflicts = append(conflicts, conflictNames("callbacks", dst.Callbacks, src.Callbacks)...)
+	sort.Strings(conflicts)
+
+	switch m.policy {
+	case ConflictOverwrite:
+	case ConflictRename:
+		for _, ref := range conflicts {
+			renames[ref] = uniqueRef(dst, src, ref, renames)
+		}
+
+		if len(renames) > 0 {
+			src = renameComponents(src, renames)
+		}
+
+	default:
+		var errs error
+		for _, ref := range conflicts {
+			errs = multierror.Append(errs, &ConflictError{Ref: ref, Origin: origin, ExistingOrigin: m.origins[ref]})
+		}
+
+		if errs != nil {
+			if multiErr, ok := errs.(*multierror.Error); ok && len(multiErr.Errors) == 1 {
+				err = multiErr.Errors[0]
+				return
+			}
+
+			err = errs
+			return
+		}
+	}
+
+	mergeMap(m, "schemas", &dst.Schemas, src.Schemas, origin)
+	mergeMap(m, "parameters", &dst.Parameters, src.Parameters, origin)
+	mergeMap(m, "headers", &dst.Headers, src.Headers, origin)
+	mergeMap(m, "requestBodies", &dst.RequestBodies, src.RequestBodies, origin)
+	mergeMap(m, "responses", &dst.Responses, src.Responses, origin)
+	mergeMap(m, "securitySchemes", &dst.SecuritySchemes, src.SecuritySchemes, origin)
+	mergeMap(m, "examples", &dst.Examples, src.Examples, origin)
+	mergeMap(m, "links", &dst.Links, src.Links, origin)
+	mergeMap(m, "callbacks", &dst.Callbacks, src.Callbacks, origin)
+	return
+}
+
+// CloneComponents returns the components with the copied maps, the component values are shared.
+func CloneComponents(components openapi3.Components) openapi3.Components {
+	cloned := openapi3.Components{ExtensionProps: components.ExtensionProps}
+	mergeMap(nil, "schemas", &cloned.Schemas, components.Schemas, "")
+	mergeMap(nil, "parameters", &cloned.Parameters, components.Parameters, "")
+	mergeMap(nil, "headers", &cloned.Headers, components.Headers, "")
+	mergeMap(nil, "requestBodies", &cloned.RequestBodies, components.RequestBodies, "")
+	mergeMap(nil, "responses", &cloned.Responses, components.Responses, "")
+	mergeMap(nil, "securitySchemes", &cloned.SecuritySchemes, components.SecuritySchemes, "")
+	mergeMap(nil, "examples", &cloned.Examples, components.Examples, "")
+	mergeMap(nil, "links", &cloned.Links, components.Links, "")
+	mergeMap(nil, "callbacks", &cloned.Callbacks, components.Callbacks, "")
+	return cloned
+}
+
+// conflictNames returns the reference of components in src which has the same name but different value in dst.
+func conflictNames[M ~map[string]V, V any](kind string, dst, src M) []string {
+	refs := make([]string, 0)
+	for name, srcValue := range src {
+		dstValue, exist := dst[name]
+		if !exist || equalJSON(dstValue, srcValue) {
+			continue
+		}
+
+		refs = append(refs, componentsRefPrefix+kind+"/"+name)
 	}
 
-	for schemaName, schemaRef := range src.Schemas {
-		dst.Schemas[schemaName] = schemaRef
+	return refs
+}
+
+// mergeMap copies src to dst, and sets the origin of the copied component if m is not nil.
+func mergeMap[M ~map[string]V, V any](m *Merger, kind string, dst *M, src M, origin string) {
+	if *dst == nil {
+		*dst = make(M)
 	}
 
-	// merge header
-	if dst.Headers == nil {
-		dst.Headers = make(map[string]*openapi3.HeaderRef)
+	for name, value := range src {
+		(*dst)[name] = value
+		if m == nil {
+			continue
+		}
+
+		ref := componentsRefPrefix + kind + "/" + name
+		if _, exist := m.origins[ref]; !exist || m.policy == ConflictOverwrite {
+			m.origins[ref] = origin
+		}
 	}
+}
 
-	for headerName, headerRef := range src.Headers {
-		dst.Headers[headerName] = headerRef
+// uniqueRef returns the reference with suffix number which is not used in dst, src and the other renamed references.
+func uniqueRef(dst *openapi3.Components, src openapi3.Components, ref string, renames map[string]string) string {
+	used := func(candidate string) bool {
+		if ComponentByRef(dst, candidate) != nil || ComponentByRef(&src, candidate) != nil {
+			return true
+		}
+
+		for _, renamed := range renames {
+			if renamed == candidate {
+				return true
+			}
+		}
+
+		return false
 	}
 
-	// merge parameters
-	if dst.Parameters == nil {
-		dst.Parameters = make(map[string]*openapi3.ParameterRef)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", ref, i)
+		if !used(candidate) {
+			return candidate
+		}
 	}
+}
+
+// renameComponents renames the component names and all references to it.
+// The components maps is copied, but the references in the component values is renamed in place,
+// so the Go value of the example and extension is kept as-is.
+func renameComponents(src openapi3.Components, renames map[string]string) openapi3.Components {
+	out := CloneComponents(src)
+	renameRefs(reflect.ValueOf(&out), renames, make(map[uintptr]struct{}))
 
-	for paramName, paramRef := range src.Parameters {
-		dst.Parameters[paramName] = paramRef
+	for ref, renamed := range renames {
+		kind, name, _ := splitRef(ref)
+		_, newName, _ := splitRef(renamed)
+		switch kind {
+		case "schemas":
+			renameKey(out.Schemas, name, newName)
+		case "parameters":
+			renameKey(out.Parameters, name, newName)
+		case "headers":
+			renameKey(out.Headers, name, newName)
+		case "requestBodies":
+			renameKey(out.RequestBodies, name, newName)
+		case "responses":
+			renameKey(out.Responses, name, newName)
+		case "securitySchemes":
+			renameKey(out.SecuritySchemes, name, newName)
+		case "examples":
+			renameKey(out.Examples, name, newName)
+		case "links":
+			renameKey(out.Links, name, newName)
+		case "callbacks":
+			renameKey(out.Callbacks, name, newName)
+		}
 	}
 
-	// merge request body
-	if dst.RequestBodies == nil {
-		dst.RequestBodies = make(map[string]*openapi3.RequestBodyRef)
+	return out
+}
+
+// renameRefs walks the openapi3 value and renames the Ref field of every reference, i.e: *openapi3.SchemaRef.
+// The interface value, i.e: example and extension, is not walked.
+func renameRefs(v reflect.Value, renames map[string]string, visited map[uintptr]struct{}) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+
+		if _, exist := visited[v.Pointer()]; exist {
+			return
+		}
+
+		visited[v.Pointer()] = struct{}{}
+		renameRefs(v.Elem(), renames, visited)
+
+	case reflect.Struct:
+		if ref := v.FieldByName("Ref"); ref.IsValid() && ref.Kind() == reflect.String && ref.CanSet() {
+			if renamed, exist := renames[ref.String()]; exist {
+				ref.SetString(renamed)
+			}
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				renameRefs(v.Field(i), renames, visited)
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			renameRefs(iter.Value(), renames, visited)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			renameRefs(v.Index(i), renames, visited)
+		}
 	}
+}
 
-	for reqBodyName, reqBodyRef := range src.RequestBodies {
-		dst.RequestBodies[reqBodyName] = reqBodyRef
+func renameKey[M ~map[string]V, V any](m M, name, newName string) {
+	if value, exist := m[name]; exist {
+		delete(m, name)
+		m[newName] = value
 	}
+}
 
-	// merge responses
-	if dst.Responses == nil {
-		dst.Responses = make(map[string]*openapi3.ResponseRef)
+// equalJSON returns true if both value has the same JSON.
+func equalJSON(a, b interface{}) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
 	}
 
-	for respName, respRef := range src.Responses {
-		dst.Responses[respName] = respRef
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
 	}
 
+	return bytes.Equal(aJSON, bJSON)
 }
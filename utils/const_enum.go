@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ConstEnums finds the enum values of the named type from the typed constants declared in the Go source of its package,
+// i.e: const ( KindCat PetKind = "cat"; KindDog PetKind = "dog" ) is the enum cat and dog of PetKind.
+// The constant name is written as x-enum-varnames, and its doc comment as x-enum-descriptions.
+//
+// The package is located and type checked from the source using go/build and go/types,
+// and the result is cached per package, so each package is only loaded once.
+// The type which package source cannot be found, i.e: declared in the test file or the main package, is skipped.
+type ConstEnums struct {
+	mu       sync.Mutex
+	fset     *token.FileSet
+	importer types.Importer
+
+	// packages map k = package import path, v = map k = type name, v = the constants in the declaration order
+	packages map[string]map[string][]typedConst
+}
+
+// typedConst is the constant of the named type.
+type typedConst struct {
+	name        string
+	description string
+	value       constant.Value
+}
+
+func NewConstEnums() *ConstEnums {
+	fset := token.NewFileSet()
+	return &ConstEnums{
+		fset:     fset,
+		importer: importer.ForCompiler(fset, "source", nil),
+		packages: make(map[string]map[string][]typedConst),
+	}
+}
+
+// ApplyEnum is the same as the ApplyEnum function, but the type which does not implement OpenAPIEnum or OpenAPIEnumValues
+// uses the enum values from its constants.
+func (c *ConstEnums) ApplyEnum(t reflect.Type, schema *openapi3.Schema) error {
+	return applyEnum(t, schema, c.EnumValues)
+}
+
+// EnumValues returns the enum values of the named type from its constants, false if it has no constant.
+func (c *ConstEnums) EnumValues(t reflect.Type) ([]EnumValue, bool) {
+	if t == nil || t.Name() == "" || t.PkgPath() == "" || t.PkgPath() == "main" {
+		return nil, false
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pkg, loaded := c.packages[t.PkgPath()]
+	if !loaded {
+		pkg = c.load(t.PkgPath())
+		c.packages[t.PkgPath()] = pkg
+	}
+
+	consts := pkg[t.Name()]
+	if len(consts) <= 0 {
+		return nil, false
+	}
+
+	values := make([]EnumValue, 0, len(consts))
+	for _, typed := range consts {
+		value, ok := constValue(t, typed.value)
+		if !ok {
+			continue
+		}
+
+		values = append(values, EnumValue{Value: value, VarName: typed.name, Description: typed.description})
+	}
+
+	return values, len(values) > 0
+}
+
+// load returns the typed constants of the package grouped by the type name, nil if the package cannot be loaded.
+// The constant with the same value as the previous one, i.e: KindDefault = KindCat, is skipped.
+func (c *ConstEnums) load(pkgPath string) map[string][]typedConst {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+
+	buildPkg, err := build.Default.Import(pkgPath, wd, 0)
+	if err != nil {
+		return nil
+	}
+
+	files := make([]*ast.File, 0)
+	for _, name := range buildPkg.GoFiles {
+		file, err := parser.ParseFile(c.fset, filepath.Join(buildPkg.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil
+		}
+
+		files = append(files, file)
+	}
+
+	// the constants of the package can be evaluated even if the other declaration has error
+	config := types.Config{Importer: c.importer, Error: func(error) {}}
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	typesPkg, _ := config.Check(pkgPath, c.fset, files, info)
+	if typesPkg == nil {
+		return nil
+	}
+
+	consts := make([]*types.Const, 0)
+	descriptions := make(map[*types.Const]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				doc := valueSpec.Doc
+				if doc == nil {
+					doc = valueSpec.Comment
+				}
+
+				// the doc comment of the declaration without parentheses, i.e: // KindCat is cat.\nconst KindCat PetKind = "cat"
+				if doc == nil && !genDecl.Lparen.IsValid() {
+					doc = genDecl.Doc
+				}
+
+				for _, ident := range valueSpec.Names {
+					constObj, ok := info.Defs[ident].(*types.Const)
+					if !ok || ident.Name == "_" {
+						continue
+					}
+
+					consts = append(consts, constObj)
+					if doc != nil {
+						descriptions[constObj] = strings.TrimSpace(doc.Text())
+					}
+				}
+			}
+		}
+	}
+
+	out := make(map[string][]typedConst)
+	for _, constObj := range consts {
+		named, ok := constObj.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != typesPkg || constObj.Val().Kind() == constant.Unknown {
+			continue
+		}
+
+		typeName := named.Obj().Name()
+		duplicate := false
+		for _, existing := range out[typeName] {
+			if constant.Compare(existing.value, token.EQL, constObj.Val()) {
+				duplicate = true
+				break
+			}
+		}
+
+		if duplicate {
+			continue
+		}
+
+		out[typeName] = append(out[typeName], typedConst{
+			name:        constObj.Name(),
+			description: descriptions[constObj],
+			value:       constObj.Val(),
+		})
+	}
+
+	return out
+}
+
+// constValue returns the constant value as the value of type t, so it is written as JSON using its json.Marshaler.
+func constValue(t reflect.Type, value constant.Value) (interface{}, bool) {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		if value.Kind() != constant.Bool {
+			return nil, false
+		}
+
+		v.SetBool(constant.BoolVal(value))
+
+	case reflect.String:
+		if value.Kind() != constant.String {
+			return nil, false
+		}
+
+		v.SetString(constant.StringVal(value))
+
+	case reflect.Float32, reflect.Float64:
+		f, _ := constant.Float64Val(constant.ToFloat(value))
+		v.SetFloat(f)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, exact := constant.Int64Val(constant.ToInt(value))
+		if !exact {
+			return nil, false
+		}
+
+		v.SetInt(i)
+
+	default:
+		u, exact := constant.Uint64Val(constant.ToInt(value))
+		if !exact {
+			return nil, false
+		}
+
+		v.SetUint(u)
+	}
+
+	return v.Interface(), true
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+)
+
+const (
+	// EnumVarNamesExtension is the vendor extension of the Go constant names of the enum values.
+	EnumVarNamesExtension = "x-enum-varnames"
+
+	// EnumDescriptionsExtension is the vendor extension of the description of the enum values.
+	EnumDescriptionsExtension = "x-enum-descriptions"
+)
+
+// OpenAPIEnum is implemented by the type which has the fixed values, so the values is not repeated in every struct tag.
+// i.e: func (PetKind) OpenAPIEnum() []any { return []any{PetKindCat, PetKindDog} }
+type OpenAPIEnum interface {
+	OpenAPIEnum() []interface{}
+}
+
+// EnumValue is the enum value with its Go constant name and description.
+type EnumValue struct {
+	Value       interface{}
+	VarName     string
+	Description string
+}
+
+// OpenAPIEnumValues is the richer OpenAPIEnum, which also writes the x-enum-varnames and x-enum-descriptions.
+// It is used instead of OpenAPIEnum if the type implements both.
+type OpenAPIEnumValues interface {
+	OpenAPIEnumValues() []EnumValue
+}
+
+// ApplyEnum writes the enum of the type which implements OpenAPIEnum or OpenAPIEnumValues to the schema.
+// Pointer is dereferenced, and the enum of the element of slice, array and map is written to
+// the items and additionalProperties schema. The value is written as its JSON value, i.e: json.Marshaler is respected.
+func ApplyEnum(t reflect.Type, schema *openapi3.Schema) error {
+	return applyEnum(t, schema, nil)
+}
+
+// applyEnum is ApplyEnum, which uses fallback to find the enum values of the type without the interface.
+func applyEnum(t reflect.Type, schema *openapi3.Schema, fallback func(t reflect.Type) ([]EnumValue, bool)) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || schema == nil {
+		return nil
+	}
+
+	values, ok := enumValues(t)
+	if !ok && fallback != nil {
+		values, ok = fallback(t)
+	}
+
+	if ok {
+		return writeEnum(schema, values)
+	}
+
+	switch t.Kind() {
+	case reflect.Array, reflect.Slice:
+		if schema.Items != nil {
+			return applyEnum(t.Elem(), schema.Items.Value, fallback)
+		}
+
+	case reflect.Map:
+		if schema.AdditionalProperties != nil {
+			return applyEnum(t.Elem(), schema.AdditionalProperties.Value, fallback)
+		}
+	}
+
+	return nil
+}
+
+// enumValues returns the enum values if the type or its pointer implements OpenAPIEnumValues or OpenAPIEnum.
+func enumValues(t reflect.Type) ([]EnumValue, bool) {
+	for _, v := range []interface{}{reflect.Zero(t).Interface(), reflect.New(t).Interface()} {
+		switch enum := v.(type) {
+		case OpenAPIEnumValues:
+			return enum.OpenAPIEnumValues(), true
+
+		case OpenAPIEnum:
+			values := make([]EnumValue, 0)
+			for _, value := range enum.OpenAPIEnum() {
+				values = append(values, EnumValue{Value: value})
+			}
+
+			return values, true
+		}
+	}
+
+	return nil, false
+}
+
+func writeEnum(schema *openapi3.Schema, values []EnumValue) error {
+	var (
+		enum         = make([]interface{}, 0, len(values))
+		varNames     = make([]interface{}, 0, len(values))
+		descriptions = make([]interface{}, 0, len(values))
+
+		hasVarName, hasDescription bool
+	)
+
+	for _, value := range values {
+		jsonValue, err := enumJSONValue(value.Value)
+		if err != nil {
+			return fmt.Errorf("invalid enum value %v: %w", value.Value, err)
+		}
+
+		enum = append(enum, jsonValue)
+		varNames = append(varNames, value.VarName)
+		descriptions = append(descriptions, value.Description)
+		hasVarName = hasVarName || value.VarName != ""
+		hasDescription = hasDescription || value.Description != ""
+	}
+
+	schema.Enum = enum
+	if !hasVarName && !hasDescription {
+		return nil
+	}
+
+	if schema.Extensions == nil {
+		schema.Extensions = make(map[string]interface{})
+	}
+
+	if hasVarName {
+		schema.Extensions[EnumVarNamesExtension] = varNames
+	}
+
+	if hasDescription {
+		schema.Extensions[EnumDescriptionsExtension] = descriptions
+	}
+
+	return nil
+}
+
+// enumJSONValue returns the value as decoded JSON, i.e: PetKind("cat") returns "cat" and 1 returns float64(1).
+func enumJSONValue(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError is the error of generating the schema of one struct field.
+// It is returned by the schema customizer, so the SchemaError can find the Go field path.
+type FieldError struct {
+	// Name is the JSON name of the field, as passed to the schema customizer
+	Name string
+
+	// Type is the Go type of the field
+	Type reflect.Type
+
+	// Tag is the struct tag of the field, set by the schema customizer.
+	// It tells apart the fields with the same JSON name and type when finding the Go field path.
+	Tag reflect.StructTag
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %s", e.Name, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// SchemaError is the error of generating the schema of the body payload.
+type SchemaError struct {
+	ContentType string
+
+	// GoType is the type of the body payload, i.e: main.Pet
+	GoType string
+
+	// FieldPath is the Go field path from the body payload type, i.e: Owner.Address.City
+	// It is empty if the error is not caused by the struct field.
+	FieldPath string
+
+	// Struct is the Go type of the struct which has the field, i.e: main.Address
+	Struct string
+	Err    error
+}
+
+// NewSchemaError returns the SchemaError of the body payload value.
+// If err contains FieldError, the field path is resolved from the value type.
+func NewSchemaError(contentType string, value interface{}, err error) *SchemaError {
+	schemaErr := &SchemaError{
+		ContentType: contentType,
+		GoType:      fmt.Sprintf("%T", value),
+		Err:         err,
+	}
+
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		var owner reflect.Type
+		schemaErr.FieldPath, owner = fieldLocation(reflect.TypeOf(value), fieldErr.Name, fieldErr.Type, fieldErr.Tag)
+		if owner != nil {
+			schemaErr.Struct = owner.String()
+		}
+
+		if schemaErr.FieldPath == "" {
+			schemaErr.FieldPath = fieldErr.Name
+		}
+	}
+
+	return schemaErr
+}
+
+// Error returns the message with the content type, Go type and field path.
+// The field is only written once, so the message of the FieldError is not repeated.
+func (e *SchemaError) Error() string {
+	err := e.Err
+	if fieldErr, ok := err.(*FieldError); ok && e.FieldPath != "" {
+		err = fieldErr.Err
+	}
+
+	msg := fmt.Sprintf("content type %s, type %s", e.ContentType, e.GoType)
+	if e.FieldPath != "" {
+		msg += fmt.Sprintf(", field %s", e.FieldPath)
+	}
+
+	if e.Struct != "" && e.Struct != e.GoType {
+		msg += fmt.Sprintf(" of struct %s", e.Struct)
+	}
+
+	return fmt.Sprintf("%s: %s", msg, err)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// FieldPath returns the Go field path of the first struct field with the JSON name and type, searched depth first in the field order.
+// It returns empty string if not found, i.e: FieldPath(Pet, "city", string) returns Owner.Address.City
+func FieldPath(root reflect.Type, jsonName string, fieldType reflect.Type) string {
+	path, _ := fieldLocation(root, jsonName, fieldType, "")
+	return path
+}
+
+// fieldLocation returns the Go field path and the struct type which has the field, see FieldPath.
+// The fields is visited in the same order as openapi3gen generates them, which stops at the first failing field.
+// The failing field is then the first field with the same JSON name, type and struct tag,
+// because the earlier one with the same name, type and tag would have failed first.
+// The empty tag matches any tag. The field type matches the pointer, slice, array and map element too,
+// because openapi3gen calls the schema customizer for the element with the field name and tag.
+func fieldLocation(root reflect.Type, jsonName string, fieldType reflect.Type, tag reflect.StructTag) (string, reflect.Type) {
+	fieldType = derefType(fieldType)
+	onPath := make(map[reflect.Type]bool)
+
+	var find func(t reflect.Type, path []string) ([]string, reflect.Type)
+	find = func(t reflect.Type, path []string) ([]string, reflect.Type) {
+		t = elemType(t)
+		if t == nil || t.Kind() != reflect.Struct || onPath[t] {
+			return nil, nil
+		}
+
+		onPath[t] = true
+		defer delete(onPath, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldPath := append(append([]string{}, path...), field.Name)
+			if found, owner := find(field.Type, fieldPath); found != nil {
+				return found, owner
+			}
+
+			if jsonFieldName(field) == jsonName && (tag == "" || field.Tag == tag) && hasType(field.Type, fieldType) {
+				return fieldPath, t
+			}
+		}
+
+		return nil, nil
+	}
+
+	path, owner := find(root, nil)
+	return strings.Join(path, "."), owner
+}
+
+// hasType returns true if t or its pointer, slice, array or map element is the target type, nil target matches any type.
+func hasType(t, target reflect.Type) bool {
+	if target == nil {
+		return true
+	}
+
+	for t != nil {
+		if derefType(t) == target {
+			return true
+		}
+
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// elemType returns the type after dereferencing the pointer, slice, array and map element.
+func elemType(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// jsonFieldName returns the name of the field in the json tag, or the field name if no json tag.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
@@ -0,0 +1,64 @@
+package utils
+
+import "github.com/getkin/kin-openapi/openapi3"
+
+// maxExampleDepth limits the example generated from the schema, so recursive schema is not infinite loop.
+const maxExampleDepth = 8
+
+// SchemaExample returns the schema example, or builds it from the default, enum, properties and items example.
+// The references must be resolved, i.e: the document is loaded using openapi3.Loader.
+func SchemaExample(schema *openapi3.Schema) interface{} {
+	return schemaExample(schema, 0)
+}
+
+func schemaExample(schema *openapi3.Schema, depth int) interface{} {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if schema.Example != nil {
+		return schema.Example
+	}
+
+	if schema.Default != nil {
+		return schema.Default
+	}
+
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+
+	switch schema.Type {
+	case "object":
+		obj := make(map[string]interface{}, len(schema.Properties))
+		for name, propRef := range schema.Properties {
+			if propRef == nil {
+				continue
+			}
+
+			obj[name] = schemaExample(propRef.Value, depth+1)
+		}
+
+		return obj
+
+	case "array":
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+
+		if item := schemaExample(schema.Items.Value, depth+1); item != nil {
+			return []interface{}{item}
+		}
+
+		return []interface{}{}
+
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+
+	return nil
+}
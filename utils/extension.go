@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateExtensions ensures that every extension key is started with x-, as required by OpenAPI specification.
+func ValidateExtensions(extensions map[string]interface{}) error {
+	for key := range extensions {
+		if !strings.HasPrefix(key, "x-") {
+			return fmt.Errorf("extension key '%s' must be started with x-", key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SanitizeComponentName replace every character that is not allowed in the components key with underscore.
+// Components key must match the regular expression ^[a-zA-Z0-9\.\-_]+$
+func SanitizeComponentName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// SchemaNamer returns the components schema name of the Go type.
+// The returned name must match the components key regular expression, see SanitizeComponentName.
+type SchemaNamer interface {
+	SchemaName(t reflect.Type) string
+}
+
+// SchemaNamerFunc is the function adapter of SchemaNamer.
+type SchemaNamerFunc func(t reflect.Type) string
+
+func (f SchemaNamerFunc) SchemaName(t reflect.Type) string {
+	return f(t)
+}
+
+// ComponentsOpt is the option of request.Request and response.Response Components.
+type ComponentsOpt struct {
+	SchemaNamer SchemaNamer
+}
+
+// WithSchemaNamer sets the SchemaNamer used to name the body schema, by default ShortSchemaName.
+func WithSchemaNamer(namer SchemaNamer) func(*ComponentsOpt) {
+	return func(o *ComponentsOpt) {
+		if namer != nil {
+			o.SchemaNamer = namer
+		}
+	}
+}
+
+func NewComponentsOpt(opts ...func(*ComponentsOpt)) *ComponentsOpt {
+	o := &ComponentsOpt{
+		SchemaNamer: SchemaNamerFunc(ShortSchemaName),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// ShortSchemaName returns the package name and the type name, i.e: main.Pet.
+// Generic type arguments is written without the package, i.e: main.Page[github.com/x/y.Pet] returns main.PageOfPet.
+// Pointer is dereferenced, and the type without name is sanitized, i.e: map[string]int returns map_string_int.
+func ShortSchemaName(t reflect.Type) string {
+	return schemaName(t, false)
+}
+
+// FullSchemaName is the same as ShortSchemaName, but uses the full import path as the package,
+// i.e: github.com/x/y.Pet returns github.com_x_y.Pet.
+func FullSchemaName(t reflect.Type) string {
+	return schemaName(t, true)
+}
+
+// schemaNamer uses ShortSchemaName, and FullSchemaName when the short name is already used by the other type.
+type schemaNamer struct {
+	mu sync.Mutex
+
+	// names map k = schema name, v = the type using the name
+	names map[string]reflect.Type
+
+	// types map k = type, v = the schema name of the type
+	types map[reflect.Type]string
+}
+
+// NewSchemaNamer returns SchemaNamer which uses the package name and the type name, i.e: main.Pet.
+// If the other type with the same package name and type name is already named, i.e: github.com/a/model.Pet
+// and github.com/b/model.Pet, the later one uses the full import path: github.com_b_model.Pet.
+func NewSchemaNamer() SchemaNamer {
+	return &schemaNamer{
+		names: make(map[string]reflect.Type),
+		types: make(map[reflect.Type]string),
+	}
+}
+
+func (n *schemaNamer) SchemaName(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return ""
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if name, exist := n.types[t]; exist {
+		return name
+	}
+
+	name := ShortSchemaName(t)
+	if other, used := n.names[name]; used && other != t {
+		name = FullSchemaName(t)
+	}
+
+	n.names[name] = t
+	n.types[t] = name
+	return name
+}
+
+func schemaName(t reflect.Type, fullPath bool) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return ""
+	}
+
+	if t.Name() == "" {
+		return SanitizeComponentName(t.String())
+	}
+
+	base, args := splitTypeArgs(t.Name())
+	name := base + typeArgsName(args)
+	if t.PkgPath() == "" {
+		return SanitizeComponentName(name)
+	}
+
+	// package name may be different with the last element of import path, i.e: main
+	pkg, _ := splitTypeArgs(t.String())
+	pkg = pkg[:strings.LastIndex(pkg, ".")+1]
+	if fullPath {
+		pkg = t.PkgPath() + "."
+	}
+
+	return SanitizeComponentName(pkg + name)
+}
+
+// splitTypeArgs returns the type name and its type arguments, i.e: Page[int,string] returns Page and int,string
+func splitTypeArgs(name string) (base, args string) {
+	start := strings.Index(name, "[")
+	if start <= 0 || !strings.HasSuffix(name, "]") {
+		return name, ""
+	}
+
+	return name[:start], name[start+1 : len(name)-1]
+}
+
+// typeArgsName returns the type arguments as name, i.e: github.com/x/y.Pet,int returns OfPetAndInt
+func typeArgsName(args string) string {
+	if args == "" {
+		return ""
+	}
+
+	names := make([]string, 0)
+	depth, start := 0, 0
+	for i, r := range args {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				names = append(names, typeArgName(args[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	names = append(names, typeArgName(args[start:]))
+	return "Of" + strings.Join(names, "And")
+}
+
+// typeArgName returns the type argument without the package, i.e: *github.com/x/y.Pet returns Pet
+func typeArgName(arg string) string {
+	arg = strings.TrimSpace(arg)
+	switch {
+	case strings.HasPrefix(arg, "*"):
+		return typeArgName(arg[1:])
+
+	case strings.HasPrefix(arg, "["):
+		// slice or array, i.e: []int or [2]int
+		if end := strings.Index(arg, "]"); end > 0 {
+			return "ListOf" + typeArgName(arg[end+1:])
+		}
+
+	case strings.HasPrefix(arg, "map["):
+		depth := 0
+		for i, r := range arg {
+			switch r {
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					return "MapOf" + typeArgName(arg[len("map["):i]) + "And" + typeArgName(arg[i+1:])
+				}
+			}
+		}
+	}
+
+	base, args := splitTypeArgs(arg)
+	if i := strings.LastIndex(base, "/"); i >= 0 {
+		base = base[i+1:]
+	}
+
+	if i := strings.LastIndex(base, "."); i >= 0 {
+		base = base[i+1:]
+	}
+
+	if base == "" {
+		return typeArgsName(args)
+	}
+
+	r, size := utf8.DecodeRuneInString(base)
+	return string(unicode.ToUpper(r)) + base[size:] + typeArgsName(args)
+}
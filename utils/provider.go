@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+)
+
+// SchemaProvider is implemented by the type which schema cannot be generated from its Go type,
+// i.e: the type with custom JSON encoding like decimal or money which is encoded as string.
+// Like json.Marshaler overrides the encoding, the returned schema is used instead of the generated one.
+// The method is called on the zero value, so it must not depend on the receiver value.
+type SchemaProvider interface {
+	OpenAPISchema() *openapi3.Schema
+}
+
+// TypeSchemas map k = Go type, v = the schema of the type.
+// It is used for the third party type which cannot implement SchemaProvider, i.e: resource.Quantity of k8s.
+type TypeSchemas map[reflect.Type]*openapi3.Schema
+
+// Register sets the schema of the type of value, i.e: Register(resource.Quantity{}, openapi3.NewStringSchema()).
+// Pointer is dereferenced, so the schema is used for both the type and its pointer.
+func (s TypeSchemas) Register(value interface{}, schema *openapi3.Schema) {
+	t := reflect.TypeOf(value)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || schema == nil {
+		return
+	}
+
+	s[t] = schema
+}
+
+// Schema returns the copy of the schema of the type, the registered one or from SchemaProvider.
+// The registered schema takes precedence, so the SchemaProvider of the third party type can be overridden too.
+// Pointer is dereferenced, and false is returned if the type has no schema.
+func (s TypeSchemas) Schema(t reflect.Type) (*openapi3.Schema, bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return nil, false
+	}
+
+	if schema, exist := s[t]; exist {
+		return copySchema(schema), true
+	}
+
+	// the method is called on the zero value, so it must not depend on the receiver value
+	for _, v := range []interface{}{reflect.Zero(t).Interface(), reflect.New(t).Interface()} {
+		if provider, ok := v.(SchemaProvider); ok {
+			if schema := provider.OpenAPISchema(); schema != nil {
+				return copySchema(schema), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// copySchema returns the deep copy of the schema, so it can be changed by the struct tag without changing the original.
+func copySchema(schema *openapi3.Schema) *openapi3.Schema {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return schema
+	}
+
+	out := &openapi3.Schema{}
+	if err = json.Unmarshal(b, out); err != nil {
+		return schema
+	}
+
+	return out
+}
@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"sort"
+	"strings"
+)
+
+// componentsRefPrefix is the prefix of local reference to the components, i.e: #/components/schemas/Pet
+const componentsRefPrefix = "#/components/"
+
+// CollectRefs returns all components reference used by the values, including the reference used by the referenced components.
+// Values is any openapi3 value which can be marshalled as JSON, i.e: *openapi3.Operation or *openapi3.PathItem.
+// The returned map k = reference, i.e: #/components/schemas/Pet
+func CollectRefs(components *openapi3.Components, values ...interface{}) map[string]struct{} {
+	refs := make(map[string]struct{})
+	queue := make([]string, 0)
+	for _, value := range values {
+		queue = append(queue, jsonRefs(value)...)
+	}
+
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if _, visited := refs[ref]; visited || !strings.HasPrefix(ref, componentsRefPrefix) {
+			continue
+		}
+
+		refs[ref] = struct{}{}
+		if component := ComponentByRef(components, ref); component != nil {
+			queue = append(queue, jsonRefs(component)...)
+		}
+	}
+
+	return refs
+}
+
+// ComponentRefs returns the sorted references of all components, i.e: #/components/schemas/Pet
+func ComponentRefs(components *openapi3.Components) []string {
+	refs := make([]string, 0)
+	if components == nil {
+		return refs
+	}
+
+	refs = append(refs, kindRefs("schemas", components.Schemas)...)
+	refs = append(refs, kindRefs("parameters", components.Parameters)...)
+	refs = append(refs, kindRefs("headers", components.Headers)...)
+	refs = append(refs, kindRefs("requestBodies", components.RequestBodies)...)
+	refs = append(refs, kindRefs("responses", components.Responses)...)
+	refs = append(refs, kindRefs("securitySchemes", components.SecuritySchemes)...)
+	refs = append(refs, kindRefs("examples", components.Examples)...)
+	refs = append(refs, kindRefs("links", components.Links)...)
+	refs = append(refs, kindRefs("callbacks", components.Callbacks)...)
+	sort.Strings(refs)
+	return refs
+}
+
+func kindRefs[M ~map[string]V, V any](kind string, components M) []string {
+	refs := make([]string, 0, len(components))
+	for name := range components {
+		refs = append(refs, componentsRefPrefix+kind+"/"+name)
+	}
+
+	return refs
+}
+
+// ComponentByRef returns the component value of the local reference, nil if not exist.
+func ComponentByRef(components *openapi3.Components, ref string) interface{} {
+	kind, name, ok := splitRef(ref)
+	if components == nil || !ok {
+		return nil
+	}
+
+	var (
+		component interface{}
+		exist     bool
+	)
+
+	switch kind {
+	case "schemas":
+		component, exist = components.Schemas[name]
+	case "parameters":
+		component, exist = components.Parameters[name]
+	case "headers":
+		component, exist = components.Headers[name]
+	case "requestBodies":
+		component, exist = components.RequestBodies[name]
+	case "responses":
+		component, exist = components.Responses[name]
+	case "securitySchemes":
+		component, exist = components.SecuritySchemes[name]
+	case "examples":
+		component, exist = components.Examples[name]
+	case "links":
+		component, exist = components.Links[name]
+	case "callbacks":
+		component, exist = components.Callbacks[name]
+	}
+
+	if !exist {
+		return nil
+	}
+
+	return component
+}
+
+// DeleteComponent deletes the component of the local reference.
+func DeleteComponent(components *openapi3.Components, ref string) {
+	kind, name, ok := splitRef(ref)
+	if components == nil || !ok {
+		return
+	}
+
+	switch kind {
+	case "schemas":
+		delete(components.Schemas, name)
+	case "parameters":
+		delete(components.Parameters, name)
+	case "headers":
+		delete(components.Headers, name)
+	case "requestBodies":
+		delete(components.RequestBodies, name)
+	case "responses":
+		delete(components.Responses, name)
+	case "securitySchemes":
+		delete(components.SecuritySchemes, name)
+	case "examples":
+		delete(components.Examples, name)
+	case "links":
+		delete(components.Links, name)
+	case "callbacks":
+		delete(components.Callbacks, name)
+	}
+}
+
+// FilterComponents returns the copy of components which only contains the references.
+// The returned maps are new maps, but the component values are shared.
+func FilterComponents(components *openapi3.Components, refs map[string]struct{}) openapi3.Components {
+	out := openapi3.Components{}
+	if components == nil {
+		return out
+	}
+
+	out.ExtensionProps = components.ExtensionProps
+	for ref := range refs {
+		kind, name, ok := splitRef(ref)
+		if !ok {
+			continue
+		}
+
+		switch kind {
+		case "schemas":
+			if v, exist := components.Schemas[name]; exist {
+				if out.Schemas == nil {
+					out.Schemas = make(openapi3.Schemas)
+				}
+
+				out.Schemas[name] = v
+			}
+		case "parameters":
+			if v, exist := components.Parameters[name]; exist {
+				if out.Parameters == nil {
+					out.Parameters = make(openapi3.ParametersMap)
+				}
+
+				out.Parameters[name] = v
+			}
+		case "headers":
+			if v, exist := components.Headers[name]; exist {
+				if out.Headers == nil {
+					out.Headers = make(openapi3.Headers)
+				}
+
+				out.Headers[name] = v
+			}
+		case "requestBodies":
+			if v, exist := components.RequestBodies[name]; exist {
+				if out.RequestBodies == nil {
+					out.RequestBodies = make(openapi3.RequestBodies)
+				}
+
+				out.RequestBodies[name] = v
+			}
+		case "responses":
+			if v, exist := components.Responses[name]; exist {
+				if out.Responses == nil {
+					out.Responses = make(openapi3.Responses)
+				}
+
+				out.Responses[name] = v
+			}
+		case "securitySchemes":
+			if v, exist := components.SecuritySchemes[name]; exist {
+				if out.SecuritySchemes == nil {
+					out.SecuritySchemes = make(openapi3.SecuritySchemes)
+				}
+
+				out.SecuritySchemes[name] = v
+			}
+		case "examples":
+			if v, exist := components.Examples[name]; exist {
+				if out.Examples == nil {
+					out.Examples = make(openapi3.Examples)
+				}
+
+				out.Examples[name] = v
+			}
+		case "links":
+			if v, exist := components.Links[name]; exist {
+				if out.Links == nil {
+					out.Links = make(openapi3.Links)
+				}
+
+				out.Links[name] = v
+			}
+		case "callbacks":
+			if v, exist := components.Callbacks[name]; exist {
+				if out.Callbacks == nil {
+					out.Callbacks = make(openapi3.Callbacks)
+				}
+
+				out.Callbacks[name] = v
+			}
+		}
+	}
+
+	return out
+}
+
+// splitRef returns the components kind and name, i.e: #/components/schemas/Pet returns schemas and Pet
+func splitRef(ref string) (kind, name string, ok bool) {
+	if !strings.HasPrefix(ref, componentsRefPrefix) {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ref, componentsRefPrefix), "/", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	// JSON pointer escape, i.e: ~1 is / and ~0 is ~
+	name = strings.ReplaceAll(strings.ReplaceAll(parts[1], "~1", "/"), "~0", "~")
+	return parts[0], name, true
+}
+
+// jsonRefs returns all $ref value in the JSON of the value.
+func jsonRefs(value interface{}) []string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var v interface{}
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+
+	refs := make([]string, 0)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if ref, ok := child.(string); ok && key == "$ref" {
+					refs = append(refs, ref)
+					continue
+				}
+
+				walk(child)
+			}
+
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+
+	walk(v)
+	return refs
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+	"strings"
+)
+
+// RequiredPolicy is how the required properties of the struct schema is decided.
+// The explicit openapi3 tag of the field always overrides the policy,
+// i.e: `openapi3:"required"` or `openapi3:"optional"`.
+type RequiredPolicy int
+
+const (
+	// RequiredExplicit only uses the explicit openapi3 tag and the required rule of the validate tag.
+	RequiredExplicit RequiredPolicy = iota
+
+	// RequiredInferred makes the non-pointer field without omitempty in the json tag required,
+	// and the pointer or omitempty field optional.
+	RequiredInferred
+)
+
+// FieldRequired returns whether the struct field is required using the policy.
+// explicit is true if the field has `openapi3:"required"` or `openapi3:"optional"`,
+// otherwise required is only true when the policy is RequiredInferred.
+func FieldRequired(field reflect.StructField, policy RequiredPolicy) (required, explicit bool) {
+	// the parse error is returned by ApplyOpenAPITag, so it is ignored here
+	entries, _ := ParseOpenAPITag(field.Tag.Get(OpenAPITag))
+	for _, entry := range entries {
+		switch {
+		case entry.Key == "required" && entry.Value == "":
+			required, explicit = true, true
+		case entry.Key == "optional":
+			required, explicit = false, true
+		}
+	}
+
+	if explicit || policy != RequiredInferred {
+		return
+	}
+
+	if field.Type.Kind() == reflect.Ptr {
+		return false, false
+	}
+
+	_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return !containsString(strings.Split(options, ","), "omitempty"), false
+}
+
+// ApplyRequiredFields adds the required fields of struct t to the required properties of the schema using the policy,
+// and removes the explicitly optional one. Embedded struct without json tag is flattened.
+// Then the required properties is validated, see ValidateRequired.
+func ApplyRequiredFields(name string, t reflect.Type, schema *openapi3.Schema, policy RequiredPolicy) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct || schema == nil {
+		return nil
+	}
+
+	applyRequiredFields(t, schema, policy)
+	return ValidateRequired(name, t, schema)
+}
+
+func applyRequiredFields(t reflect.Type, schema *openapi3.Schema, policy RequiredPolicy) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				applyRequiredFields(embedded, schema, policy)
+				continue
+			}
+		}
+
+		name := jsonFieldName(field)
+		if _, exist := schema.Properties[name]; name == "-" || !exist {
+			continue
+		}
+
+		required, explicit := FieldRequired(field, policy)
+		switch {
+		case required && !containsString(schema.Required, name):
+			schema.Required = append(schema.Required, name)
+		case !required && explicit:
+			schema.Required = removeString(schema.Required, name)
+		}
+	}
+}
+
+// ValidateRequired returns *FieldError if the required properties of the struct schema is not the property name,
+// i.e: the stale name in `openapi3:"required:'id;name'"` after the field is renamed.
+// name and t is the field which has the schema.
+func ValidateRequired(name string, t reflect.Type, schema *openapi3.Schema) error {
+	if schema == nil || schema.Properties == nil {
+		return nil
+	}
+
+	for _, required := range schema.Required {
+		if _, exist := schema.Properties[required]; !exist {
+			return &FieldError{Name: name, Type: t, Err: fmt.Errorf("required property '%s' does not exist", required)}
+		}
+	}
+
+	return nil
+}
+
+func removeString(values []string, value string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+
+	if len(out) <= 0 {
+		return nil
+	}
+
+	return out
+}
@@ -0,0 +1,404 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OpenAPITag is the struct tag of the schema keywords, i.e: `openapi3:"desc:Name of the pet,ex:kitty,maxLength:50"`
+const OpenAPITag = "openapi3"
+
+// openAPIKeywords is the supported keywords of the openapi3 tag, other than vendor extension.
+var openAPIKeywords = map[string]struct{}{
+	"desc": {}, "title": {}, "ex": {}, "default": {}, "enum": {}, "format": {}, "pattern": {},
+	"min": {}, "max": {}, "exclusiveMin": {}, "exclusiveMax": {}, "minLength": {}, "maxLength": {},
+	"minItems": {}, "maxItems": {}, "uniqueItems": {}, "nullable": {}, "readOnly": {}, "writeOnly": {},
+	"deprecated": {}, "required": {}, "optional": {},
+}
+
+// TagEntry is one keyword and its value in the openapi3 tag.
+type TagEntry struct {
+	Key   string
+	Value string
+}
+
+// ParseOpenAPITag parses the openapi3 tag value into the entries in the written order.
+// Entries are separated by comma, the key and value is separated by the first colon,
+// so the value may contain colon, i.e: ex:https://example.com. Key without value is allowed, i.e: nullable.
+// Value can be quoted using single quote to contain comma, i.e: desc:'name, age and sound'.
+// The single quote is only the quote when it is the first character of the value, otherwise it is kept as is,
+// i.e: desc:Pet's name. Backslash escapes the next character, i.e: desc:a\,b (written as \\ in the Go struct tag).
+//
+// Breaking change: before, every single quote in the value was removed, colon was replaced with space
+// and backslash was kept as is, i.e: desc:Pet's name and ex:https://example.com was written as
+// "Pets name" and "https //example.com", now it is written as "Pet's name" and "https://example.com".
+// A value starting with a single quote must be closed, otherwise the unterminated quote is an error.
+func ParseOpenAPITag(tagValue string) ([]TagEntry, error) {
+	entries := make([]TagEntry, 0)
+	var (
+		key, value              strings.Builder
+		inValue, quoted, closed bool
+		escaped                 bool
+	)
+
+	flush := func() error {
+		entry := TagEntry{Key: strings.TrimSpace(key.String()), Value: value.String()}
+		key.Reset()
+		value.Reset()
+		hasValue := inValue
+		inValue, closed = false, false
+
+		if entry.Key == "" {
+			if hasValue {
+				return fmt.Errorf("missing key of value '%s'", entry.Value)
+			}
+
+			return nil
+		}
+
+		entries = append(entries, entry)
+		return nil
+	}
+
+	for _, r := range tagValue {
+		current := &key
+		if inValue {
+			current = &value
+		}
+
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '\'' && inValue && quoted:
+			quoted, closed = false, true
+		case r == '\'' && inValue && !closed && value.Len() == 0:
+			quoted = true
+		case r == ':' && !inValue:
+			inValue = true
+		case r == ',' && !quoted:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	switch {
+	case escaped:
+		return nil, fmt.Errorf("unfinished escape at the end of '%s'", tagValue)
+	case quoted:
+		return nil, fmt.Errorf("unterminated quote in '%s'", tagValue)
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ApplyOpenAPITag writes the keywords of the openapi3 tag value to the schema of the field name with type t.
+// Supported keywords:
+// * desc, title: description and title.
+// * ex, default: example and default value, coerced to the Go kind of t. Array value is separated by semicolon.
+// * enum: values separated by semicolon, coerced to the Go kind of t. For array, it is the enum of the items
+// coerced to the Go kind of the element, other array and object is an error.
+// * format, pattern: only for non array and object.
+// * min, max, exclusiveMin, exclusiveMax: only for integer and number.
+// * minLength, maxLength: only for string.
+// * minItems, maxItems, uniqueItems: only for array.
+// * nullable, readOnly, writeOnly, deprecated, uniqueItems, exclusiveMin, exclusiveMax: true if the value is empty.
+// * required: required properties separated by semicolon, only for object.
+// Without value, the field itself is required in its parent struct, see ApplyRequiredFields.
+// * optional: the field is not required in its parent struct, see ApplyRequiredFields.
+// * x-*: vendor extension, value is used as JSON value if it is valid JSON, otherwise as string.
+//
+// The parse and coerce error is returned as *FieldError. If strict is true, unknown keyword is an error too.
+func ApplyOpenAPITag(name string, t reflect.Type, tagValue string, schema *openapi3.Schema, strict bool) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || schema == nil || strings.TrimSpace(tagValue) == "" {
+		return nil
+	}
+
+	entries, err := ParseOpenAPITag(tagValue)
+	if err != nil {
+		return &FieldError{Name: name, Type: t, Err: fmt.Errorf("invalid %s tag: %w", OpenAPITag, err)}
+	}
+
+	for _, entry := range entries {
+		_, known := openAPIKeywords[entry.Key]
+		if strict && !known && !strings.HasPrefix(entry.Key, "x-") {
+			return &FieldError{Name: name, Type: t, Err: fmt.Errorf("unknown %s tag keyword '%s'", OpenAPITag, entry.Key)}
+		}
+
+		if err = applyKeyword(t, entry.Key, entry.Value, schema); err != nil {
+			return &FieldError{Name: name, Type: t, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func applyKeyword(t reflect.Type, k, v string, schema *openapi3.Schema) (err error) {
+	scalar := schema.Type != "array" && schema.Type != "object"
+	switch k {
+	case "desc":
+		if v != "" {
+			schema.Description = v
+		}
+
+	case "title":
+		schema.Title = v
+
+	case "ex":
+		var exampleVal interface{}
+		exampleVal, err = exampleValue(t, v)
+		if err != nil {
+			return fmt.Errorf("invalid example value '%s': %w", v, err)
+		}
+
+		if exampleVal != nil {
+			schema.Example = exampleVal
+		}
+
+	case "default":
+		schema.Default, err = coerceValue(t, v)
+		if err != nil {
+			return fmt.Errorf("invalid default value '%s': %w", v, err)
+		}
+
+	case "enum":
+		// the enum of the array is the enum of its items, i.e: []string with enum:cat;dog
+		target, elem := schema, t
+		if !scalar {
+			if schema.Type != "array" || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) ||
+				schema.Items == nil || schema.Items.Value == nil {
+				return fmt.Errorf("enum is only for the scalar or the array of scalar, not %s", schema.Type)
+			}
+
+			target, elem = schema.Items.Value, t.Elem()
+			for elem.Kind() == reflect.Pointer {
+				elem = elem.Elem()
+			}
+
+			if target.Type == "array" || target.Type == "object" {
+				return fmt.Errorf("enum is only for the scalar or the array of scalar, not array of %s", target.Type)
+			}
+		}
+
+		values := make([]interface{}, 0)
+		for _, value := range strings.Split(v, ";") {
+			var enumVal interface{}
+			enumVal, err = coerceValue(elem, value)
+			if err != nil {
+				return fmt.Errorf("invalid enum value '%s': %w", value, err)
+			}
+
+			values = append(values, enumVal)
+		}
+
+		target.Enum = values
+
+	case "format":
+		if scalar {
+			schema.Format = v
+		}
+
+	case "pattern":
+		if scalar {
+			schema.Pattern = v
+		}
+
+	case "min", "max":
+		if schema.Type != "integer" && schema.Type != "number" {
+			return nil
+		}
+
+		var f float64
+		f, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s value '%s': %w", k, v, err)
+		}
+
+		if k == "min" {
+			schema.Min = &f
+		} else {
+			schema.Max = &f
+		}
+
+	case "exclusiveMin", "exclusiveMax":
+		if schema.Type != "integer" && schema.Type != "number" {
+			return nil
+		}
+
+		var b bool
+		b, err = flagValue(k, v)
+		if k == "exclusiveMin" {
+			schema.ExclusiveMin = b
+		} else {
+			schema.ExclusiveMax = b
+		}
+
+	case "minLength", "maxLength":
+		if schema.Type != "string" {
+			return nil
+		}
+
+		var n uint64
+		n, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s value '%s': %w", k, v, err)
+		}
+
+		if k == "minLength" {
+			schema.MinLength = n
+		} else {
+			schema.MaxLength = &n
+		}
+
+	case "minItems", "maxItems":
+		if schema.Type != "array" {
+			return nil
+		}
+
+		var n uint64
+		n, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s value '%s': %w", k, v, err)
+		}
+
+		if k == "minItems" {
+			schema.MinItems = n
+		} else {
+			schema.MaxItems = &n
+		}
+
+	case "uniqueItems":
+		if schema.Type == "array" {
+			schema.UniqueItems, err = flagValue(k, v)
+		}
+
+	case "nullable":
+		schema.Nullable, err = flagValue(k, v)
+	case "readOnly":
+		schema.ReadOnly, err = flagValue(k, v)
+	case "writeOnly":
+		schema.WriteOnly, err = flagValue(k, v)
+	case "deprecated":
+		schema.Deprecated, err = flagValue(k, v)
+
+	case "required":
+		// without value, it is the field itself which is required, see ApplyRequiredFields
+		if v == "" {
+			return nil
+		}
+
+		switch t.Kind() {
+		case reflect.Map, reflect.Struct:
+			if requiredField := strings.Split(v, ";"); len(requiredField) > 0 {
+				schema.Required = requiredField
+			}
+		}
+
+	default:
+		// vendor extension, i.e: x-order:1 or x-internal:true.
+		// Value is used as JSON value if it is valid JSON, otherwise as string.
+		// Extension without value, i.e: x-internal, is treated as true.
+		if strings.HasPrefix(k, "x-") {
+			var extVal interface{} = true
+			if v != "" {
+				if err := json.Unmarshal([]byte(v), &extVal); err != nil {
+					extVal = v
+				}
+			}
+
+			if schema.Extensions == nil {
+				schema.Extensions = make(map[string]interface{})
+			}
+
+			schema.Extensions[k] = extVal
+		}
+	}
+
+	return
+}
+
+// exampleValue returns the example value of the ex keyword.
+// For string, the last value separated by semicolon is used, i.e: a;b returns b.
+func exampleValue(t reflect.Type, v string) (interface{}, error) {
+	if t.Kind() == reflect.String {
+		vArr := strings.Split(v, ";")
+		return vArr[len(vArr)-1], nil
+	}
+
+	return coerceValue(t, v)
+}
+
+// coerceValue returns the value as the Go kind of t, array value is separated by semicolon.
+func coerceValue(t reflect.Type, v string) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// the value must fit the size of the type, i.e: -300 is out of range of int8
+		i, err := strconv.ParseInt(v, 10, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+
+		return int(i), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(v, 10, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+
+		return u, nil
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(v, t.Bits())
+
+	case reflect.Bool:
+		return strconv.ParseBool(v)
+
+	case reflect.Array, reflect.Slice:
+		values := make([]interface{}, 0)
+		for _, value := range strings.Split(v, ";") {
+			elemVal, err := coerceValue(t.Elem(), value)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, elemVal)
+		}
+
+		return values, nil
+
+	default:
+		return v, nil
+	}
+}
+
+// flagValue returns true if the value is empty, otherwise parse the value as bool.
+func flagValue(k, v string) (bool, error) {
+	if v == "" {
+		return true, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value '%s': %w", k, v, err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,322 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidatorExtension is the vendor extension of the validator rules which cannot be written as schema constraints.
+const ValidatorExtension = "x-validate"
+
+// validatorFormats map k = validator rule, v = schema format
+var validatorFormats = map[string]string{
+	"email":    "email",
+	"uuid":     "uuid",
+	"uuid3":    "uuid",
+	"uuid4":    "uuid",
+	"uuid5":    "uuid",
+	"uri":      "uri",
+	"url":      "uri",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+}
+
+// datetimeFormats map k = layout of the datetime rule, v = schema format.
+// Other layout is not the format of OpenAPI, so it is written to the x-validate extension.
+var datetimeFormats = map[string]string{
+	time.RFC3339:     "date-time",
+	time.RFC3339Nano: "date-time",
+	"2006-01-02":     "date",
+}
+
+// ApplyValidatorTag writes the go-playground/validator rules, i.e: `validate:"required,min=1,max=50,email"`,
+// to the schema constraints based on the schema type:
+// * min, max, len, gte, lte, gt, lt is minLength and maxLength for string, minItems and maxItems for array,
+// minProperties and maxProperties for object, and minimum and maximum for integer and number.
+// * oneof is enum.
+// * email, uuid, uri, ipv4, ipv6 and hostname is format.
+// * datetime is format date-time for RFC3339 layout and date for 2006-01-02 layout, i.e: datetime=2006-01-02.
+// * rules after dive is written to the array items.
+// * required is returned, because it is written to the parent schema.
+// The other rules are written as is to the x-validate extension.
+func ApplyValidatorTag(tag string, schema *openapi3.Schema) (required bool, err error) {
+	if schema == nil || strings.TrimSpace(tag) == "" {
+		return
+	}
+
+	// rules after dive is the rules of the array items
+	rules := strings.Split(tag, ",")
+	var (
+		itemRules []string
+		dive      bool
+	)
+
+	for i, rule := range rules {
+		if strings.TrimSpace(rule) == "dive" {
+			rules, itemRules, dive = rules[:i], rules[i+1:], true
+			break
+		}
+	}
+
+	unknown := make([]string, 0)
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "", "omitempty":
+		case "required":
+			required = true
+
+		case "min", "gte", "gt":
+			err = validatorMin(schema, param, name == "gt")
+		case "max", "lte", "lt":
+			err = validatorMax(schema, param, name == "lt")
+		case "len":
+			err = validatorMin(schema, param, false)
+			if err == nil {
+				err = validatorMax(schema, param, false)
+			}
+
+		case "oneof":
+			err = validatorEnum(schema, param)
+
+		case "datetime":
+			if format, exist := datetimeFormats[param]; exist && schema.Type == "string" {
+				schema.Format = format
+				continue
+			}
+
+			unknown = append(unknown, rule)
+
+		default:
+			if format, exist := validatorFormats[name]; exist && schema.Type == "string" {
+				schema.Format = format
+				continue
+			}
+
+			unknown = append(unknown, rule)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("invalid validate rule '%s': %w", rule, err)
+			return
+		}
+	}
+
+	if dive {
+		if schema.Items == nil || schema.Items.Value == nil {
+			unknown = append(append(unknown, "dive"), itemRules...)
+		} else if _, err = ApplyValidatorTag(strings.Join(itemRules, ","), schema.Items.Value); err != nil {
+			return
+		}
+	}
+
+	if len(unknown) > 0 {
+		if schema.Extensions == nil {
+			schema.Extensions = make(map[string]interface{})
+		}
+
+		schema.Extensions[ValidatorExtension] = strings.Join(unknown, ",")
+	}
+
+	return
+}
+
+// ApplyValidatorTags applies ApplyValidatorTag of each struct field to its property schema using the JSON name,
+// and appends the required field to the struct schema. Embedded struct without json tag is flattened.
+func ApplyValidatorTags(t reflect.Type, schema *openapi3.Schema) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct || schema == nil {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			if err := ApplyValidatorTags(field.Type, schema); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		name := jsonFieldName(field)
+		property := schema.Properties[name]
+		if name == "-" || property == nil {
+			continue
+		}
+
+		// the referenced schema is not changed, only the required rule is used
+		value := property.Value
+		if value == nil {
+			value = &openapi3.Schema{}
+		}
+
+		required, err := ApplyValidatorTag(field.Tag.Get("validate"), value)
+		if err != nil {
+			return &FieldError{Name: name, Type: field.Type, Tag: field.Tag, Err: err}
+		}
+
+		if required && !containsString(schema.Required, name) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validatorMin(schema *openapi3.Schema, param string, exclusive bool) error {
+	switch schema.Type {
+	case "integer", "number":
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return err
+		}
+
+		schema.Min = &v
+		schema.ExclusiveMin = exclusive
+		return nil
+	}
+
+	v, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	// length greater than N is the same as length at least N+1
+	if exclusive {
+		v++
+	}
+
+	switch schema.Type {
+	case "string":
+		schema.MinLength = v
+	case "array":
+		schema.MinItems = v
+	case "object":
+		schema.MinProps = v
+	}
+
+	return nil
+}
+
+func validatorMax(schema *openapi3.Schema, param string, exclusive bool) error {
+	switch schema.Type {
+	case "integer", "number":
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return err
+		}
+
+		schema.Max = &v
+		schema.ExclusiveMax = exclusive
+		return nil
+	}
+
+	v, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	// length less than N is the same as length at most N-1
+	if exclusive {
+		if v == 0 {
+			return fmt.Errorf("length must be greater than 0")
+		}
+
+		v--
+	}
+
+	switch schema.Type {
+	case "string":
+		schema.MaxLength = &v
+	case "array":
+		schema.MaxItems = &v
+	case "object":
+		schema.MaxProps = &v
+	}
+
+	return nil
+}
+
+// validatorEnum writes the space separated values as enum, i.e: oneof=cat dog or oneof='red green' blue
+func validatorEnum(schema *openapi3.Schema, param string) error {
+	values := make([]interface{}, 0)
+	for _, value := range splitOneOf(param) {
+		switch schema.Type {
+		case "integer":
+			v, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			values = append(values, v)
+
+		case "number":
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+
+			values = append(values, v)
+
+		default:
+			values = append(values, value)
+		}
+	}
+
+	schema.Enum = values
+	return nil
+}
+
+// splitOneOf splits the oneof parameter by space, the value with space can be quoted using single quote.
+func splitOneOf(param string) []string {
+	values := make([]string, 0)
+	var (
+		current strings.Builder
+		quoted  bool
+	)
+
+	for _, r := range param {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				values = append(values, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if current.Len() > 0 {
+		values = append(values, current.String())
+	}
+
+	return values
+}